@@ -0,0 +1,250 @@
+package globify
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bernardoforcillo/globify/internal/app"
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/processor"
+)
+
+// Exit codes returned by Run
+const (
+	// ExitOK means the command completed successfully
+	ExitOK = 0
+	// ExitError means the command failed
+	ExitError = 1
+	// ExitUsage means the command line was invalid
+	ExitUsage = 2
+	// ExitCheckFailed means check found problems in the translation files
+	ExitCheckFailed = 3
+)
+
+// options holds the global flags shared by every command
+type options struct {
+	configFile string
+	languages  string
+	verbose    bool
+	dryRun     bool
+	deadline   time.Duration
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a globify subcommand
+type command struct {
+	name    string
+	summary string
+	// arguments describes the positional arguments, commands without it accept none
+	arguments string
+	// flags registers the command specific flags and returns the function running the command
+	flags func(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int
+}
+
+// commands returns every available subcommand
+func commands() []command {
+	return []command{
+		{name: "translate", summary: "Translate the base language file into every target language", flags: translateCommand},
+		{name: "check", summary: "Report missing, extra, stale and mismatched keys in the target language files", flags: checkCommand},
+		{name: "init", summary: "Create a globify.config.json file from the detected translation files", flags: initCommand},
+		{name: "stats", summary: "Show the translation progress of every target language", flags: statsCommand},
+		{name: "prune", summary: "Remove keys that no longer exist in the base language file", flags: pruneCommand},
+		{name: "export", summary: "Write the untranslated and stale keys of every target language to XLIFF files", flags: exportCommand},
+		{name: "import", summary: "Merge reviewed translations from XLIFF files and mark them as approved", arguments: "<file>...", flags: importCommand},
+		{name: "glossary", summary: "Create, update, list or delete the DeepL glossaries of the target languages", arguments: "<create|update|list|delete> [file|language]...", flags: glossaryCommand},
+	}
+}
+
+// Run parses the command line arguments, runs the selected command and returns its exit code
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := &options{stdin: stdin, stdout: stdout, stderr: stderr}
+	log.SetOutput(stderr)
+
+	global := newFlagSet("globify", opts)
+	global.Usage = func() { printUsage(stderr) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+
+	// Translating is the default so a bare "globify" keeps working
+	name := "translate"
+	rest := global.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	if name == "help" {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != name {
+			continue
+		}
+
+		fs := newFlagSet("globify "+cmd.name, opts)
+		run := cmd.flags(fs, opts)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "Usage: globify %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.arguments, cmd.summary)
+			fs.PrintDefaults()
+		}
+		if err := fs.Parse(rest); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return ExitOK
+			}
+			return ExitUsage
+		}
+		if cmd.arguments == "" && fs.NArg() > 0 {
+			fmt.Fprintf(stderr, "Error: unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+			return ExitUsage
+		}
+
+		ctx, stop := opts.context()
+		defer stop()
+		return run(ctx, fs.Args())
+	}
+
+	fmt.Fprintf(stderr, "Error: unknown command %q\n\n", name)
+	printUsage(stderr)
+	return ExitUsage
+}
+
+// newFlagSet creates a flag set with the global flags registered
+func newFlagSet(name string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(opts.stderr)
+	fs.StringVar(&opts.configFile, "config", opts.configFile, "path of the configuration file (default \""+config.DefaultFileName+"\")")
+	fs.StringVar(&opts.languages, "lang", opts.languages, "comma separated target languages to work on (default all configured languages)")
+	fs.BoolVar(&opts.verbose, "verbose", opts.verbose, "log every step")
+	fs.BoolVar(&opts.dryRun, "dry-run", opts.dryRun, "show what would change without translating or writing files")
+	fs.DurationVar(&opts.deadline, "deadline", opts.deadline, "stop translating after this long, like 10m, keeping the finished translations")
+	return fs
+}
+
+// printUsage writes the general help text
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: globify [flags] <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nGlobal flags:\n")
+	fmt.Fprintf(w, "  --config string   path of the configuration file (default %q)\n", config.DefaultFileName)
+	fmt.Fprintf(w, "  --lang string     comma separated target languages to work on\n")
+	fmt.Fprintf(w, "  --verbose         log every step\n")
+	fmt.Fprintf(w, "  --dry-run         show what would change without translating or writing files\n")
+	fmt.Fprintf(w, "  --deadline value  stop translating after this long, like 10m, keeping the finished translations\n")
+	fmt.Fprintf(w, "\nRun 'globify <command> -h' for the flags of a command.\n")
+}
+
+// context returns the context of a command, cancelled by the first
+// interrupt or termination signal and once the deadline, if any, passes.
+// Later signals kill the process as usual.
+func (o *options) context() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if o.deadline <= 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.deadline)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// loadConfig loads the configuration selected by the global flags
+func (o *options) loadConfig() (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+	if o.configFile == "" {
+		cfg, err = config.LoadConfig()
+	} else {
+		cfg, err = config.LoadConfigFile(o.configFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if o.languages != "" {
+		var selected []string
+		for _, lang := range splitList(o.languages) {
+			if !contains(cfg.Languages, lang) {
+				return nil, fmt.Errorf("language '%s' is not configured", lang)
+			}
+			selected = append(selected, lang)
+		}
+		cfg.Languages = selected
+	}
+
+	return cfg, nil
+}
+
+// newApp creates the App for a command. Commands that never translate
+// do not need a translator, so no API key is required for them; their
+// processor is only used to estimate translation costs.
+func (o *options) newApp(withTranslator bool) (*app.App, error) {
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var globify *app.App
+	if withTranslator {
+		globify, err = app.NewAppWithConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize application: %w", err)
+		}
+	} else {
+		fm, err := files.NewFileManager(cfg.FileExtension)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file manager: %w", err)
+		}
+		proc, err := processor.CreateProcessor(cfg.TranslationType, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create processor: %w", err)
+		}
+		globify = app.NewAppWithDependencies(cfg, nil, fm, proc)
+	}
+
+	globify.SetVerbose(o.verbose)
+	globify.SetDryRun(o.dryRun)
+	globify.SetOutput(o.stdout)
+	return globify, nil
+}
+
+// fail reports an error and returns the generic failure exit code
+func (o *options) fail(err error) int {
+	fmt.Fprintf(o.stderr, "Error: %v\n", err)
+	return ExitError
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
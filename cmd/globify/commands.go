@@ -0,0 +1,407 @@
+package globify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bernardoforcillo/globify/internal/app"
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/xliff"
+)
+
+// translateCommand translates the base language file into every target language
+func translateCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	ignoreQuota := fs.Bool("ignore-quota", false, "translate even when the run would exceed the provider quota")
+
+	return func(ctx context.Context, args []string) int {
+		// A dry run only plans, so it works without an API key
+		globify, err := opts.newApp(!opts.dryRun)
+		if err != nil {
+			return opts.fail(err)
+		}
+		globify.SetIgnoreQuota(*ignoreQuota)
+
+		if err := globify.Run(ctx); err != nil {
+			return opts.fail(err)
+		}
+		return ExitOK
+	}
+}
+
+// checkCommand reports problems in the target language files without modifying them
+func checkCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	format := fs.String("format", "text", "output format, 'text' or 'json'")
+
+	return func(ctx context.Context, args []string) int {
+		if *format != "text" && *format != "json" {
+			fmt.Fprintf(opts.stderr, "Error: unsupported format %q\n", *format)
+			return ExitUsage
+		}
+
+		globify, err := opts.newApp(false)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		issues, err := globify.Check()
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		if *format == "json" {
+			report := struct {
+				Issues []app.Issue `json:"issues"`
+			}{Issues: issues}
+			if report.Issues == nil {
+				report.Issues = []app.Issue{}
+			}
+
+			encoder := json.NewEncoder(opts.stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				return opts.fail(err)
+			}
+		} else {
+			for _, issue := range issues {
+				fmt.Fprintf(opts.stdout, "%s: %s key '%s': %s\n", issue.Language, issue.Kind, issue.Key, issue.Message)
+			}
+			if len(issues) > 0 {
+				fmt.Fprintf(opts.stdout, "Found %d issues\n", len(issues))
+			} else {
+				fmt.Fprintln(opts.stdout, "All translation files are up to date")
+			}
+		}
+
+		if len(issues) > 0 {
+			return ExitCheckFailed
+		}
+		return ExitOK
+	}
+}
+
+// initCommand writes a new configuration file, guessing its values from the
+// translation files found in the project and asking the user to confirm them
+func initCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	folder := fs.String("folder", "", "folder containing the translation files (default detected)")
+	baseLanguage := fs.String("base", "", "base language (default detected)")
+	languages := fs.String("languages", "", "comma separated target languages (default detected)")
+	translationType := fs.String("type", "", "translation type, 'simple-json' or 'ast-json' (default detected)")
+	fileExtension := fs.String("extension", "", "extension of the translation files (default detected)")
+	yes := fs.Bool("yes", false, "use the detected and given values without prompting")
+	force := fs.Bool("force", false, "overwrite an existing configuration file")
+
+	return func(ctx context.Context, args []string) int {
+		configFile := opts.configFile
+		if configFile == "" {
+			configFile = config.DefaultFileName
+		}
+
+		if _, err := os.Stat(configFile); err == nil && !*force {
+			return opts.fail(fmt.Errorf("%s already exists, use --force to overwrite it", configFile))
+		}
+
+		cfg := config.Config{
+			TranslationType: "simple-json",
+			FileExtension:   "json",
+			BaseLanguage:    "en",
+			Folder:          "translations",
+		}
+		if detection, err := config.Detect(filepath.Dir(configFile)); err == nil {
+			cfg = detection.Config
+			// The detected folder is relative to the configuration file, while
+			// commands read it relative to the working directory
+			cfg.Folder = filepath.Join(filepath.Dir(configFile), cfg.Folder)
+			fmt.Fprintf(opts.stdout, "Detected %d translation files in %s\n", len(detection.Files), cfg.Folder)
+		} else {
+			fmt.Fprintf(opts.stdout, "No translation files detected, using default values\n")
+		}
+
+		// Explicit flags win over detected values
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "folder":
+				cfg.Folder = *folder
+			case "base":
+				cfg.BaseLanguage = *baseLanguage
+			case "languages":
+				cfg.Languages = splitList(*languages)
+			case "type":
+				cfg.TranslationType = *translationType
+			case "extension":
+				cfg.FileExtension = *fileExtension
+			}
+		})
+
+		if !*yes {
+			reader := bufio.NewReader(opts.stdin)
+			answers := []struct {
+				label string
+				value *string
+			}{
+				{"Folder", &cfg.Folder},
+				{"File extension", &cfg.FileExtension},
+				{"Base language", &cfg.BaseLanguage},
+				{"Translation type (simple-json, ast-json)", &cfg.TranslationType},
+			}
+			for _, answer := range answers {
+				*answer.value = prompt(reader, opts.stdout, answer.label, *answer.value)
+			}
+			cfg.Languages = splitList(prompt(reader, opts.stdout, "Target languages", strings.Join(cfg.Languages, ",")))
+		}
+
+		if err := cfg.Save(configFile); err != nil {
+			return opts.fail(err)
+		}
+
+		fmt.Fprintf(opts.stdout, "Created %s\n", configFile)
+		return ExitOK
+	}
+}
+
+// prompt asks for a value, returning the default when the answer is empty
+func prompt(reader *bufio.Reader, w io.Writer, label, defaultValue string) string {
+	fmt.Fprintf(w, "%s [%s]: ", label, defaultValue)
+
+	answer, _ := reader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return defaultValue
+}
+
+// statsCommand prints the translation progress of every target language
+func statsCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		globify, err := opts.newApp(false)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		stats, err := globify.Stats()
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		w := tabwriter.NewWriter(opts.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LANGUAGE\tKEYS\tTRANSLATED\tMISSING\tEXTRA\tPROGRESS")
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f%%\n", s.Language, s.Keys, s.Translated, s.Missing, s.Extra, s.Progress())
+		}
+		w.Flush()
+		return ExitOK
+	}
+}
+
+// pruneCommand removes keys that no longer exist in the base language file
+func pruneCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		globify, err := opts.newApp(false)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		results, err := globify.Prune()
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		action := "removed"
+		if opts.dryRun {
+			action = "would remove"
+		}
+		for _, result := range results {
+			for _, key := range result.Removed {
+				fmt.Fprintf(opts.stdout, "%s: %s '%s'\n", result.Language, action, key)
+			}
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(opts.stdout, "Nothing to prune")
+		}
+		return ExitOK
+	}
+}
+
+// exportCommand writes the keys that need a translation to XLIFF files for human translators
+func exportCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	format := fs.String("format", "xliff", "export format, only 'xliff' is supported")
+	version := fs.String("xliff-version", xliff.Version12, "XLIFF version, '1.2' or '2.0'")
+	output := fs.String("output", "xliff", "folder the XLIFF files are written to")
+
+	return func(ctx context.Context, args []string) int {
+		if *format != "xliff" {
+			fmt.Fprintf(opts.stderr, "Error: unsupported format %q\n", *format)
+			return ExitUsage
+		}
+		if *version != xliff.Version12 && *version != xliff.Version20 {
+			fmt.Fprintf(opts.stderr, "Error: unsupported XLIFF version %q\n", *version)
+			return ExitUsage
+		}
+
+		globify, err := opts.newApp(false)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		results, err := globify.Export(*output, *version)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		action := "exported"
+		if opts.dryRun {
+			action = "would export"
+		}
+		for _, result := range results {
+			fmt.Fprintf(opts.stdout, "%s: %s %d keys to %s\n", result.Language, action, len(result.Keys), result.File)
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(opts.stdout, "Nothing to export")
+		}
+		return ExitOK
+	}
+}
+
+// importCommand merges reviewed XLIFF files back into the target language files
+func importCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	return func(ctx context.Context, args []string) int {
+		if len(args) == 0 {
+			fmt.Fprintln(opts.stderr, "Error: no XLIFF file given")
+			return ExitUsage
+		}
+
+		globify, err := opts.newApp(false)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		action := "imported"
+		if opts.dryRun {
+			action = "would import"
+		}
+		for _, file := range args {
+			result, err := globify.Import(file)
+			if err != nil {
+				return opts.fail(err)
+			}
+
+			fmt.Fprintf(opts.stdout, "%s: %s %d keys from %s\n", result.Language, action, len(result.Imported), result.File)
+			if len(result.Skipped) > 0 {
+				fmt.Fprintf(opts.stdout, "%s: skipped %d unfinished or outdated keys\n", result.Language, len(result.Skipped))
+			}
+		}
+		return ExitOK
+	}
+}
+
+// glossaryCommand manages the DeepL glossaries used to enforce terminology.
+// Glossary files are named after their target language, like glossaries/de.csv.
+func glossaryCommand(fs *flag.FlagSet, opts *options) func(ctx context.Context, args []string) int {
+	target := fs.String("target", "", "target language of the glossary file (default the file name)")
+
+	return func(ctx context.Context, args []string) int {
+		if len(args) == 0 {
+			fmt.Fprintln(opts.stderr, "Error: no glossary action given, use create, update, list or delete")
+			return ExitUsage
+		}
+		action, args := args[0], args[1:]
+
+		switch action {
+		case "list":
+			if len(args) > 0 {
+				fmt.Fprintf(opts.stderr, "Error: unexpected arguments: %s\n", strings.Join(args, " "))
+				return ExitUsage
+			}
+		case "create", "update":
+			if len(args) == 0 {
+				fmt.Fprintln(opts.stderr, "Error: no glossary file given")
+				return ExitUsage
+			}
+			if *target != "" && len(args) > 1 {
+				fmt.Fprintln(opts.stderr, "Error: --target needs a single glossary file")
+				return ExitUsage
+			}
+		case "delete":
+			if len(args) == 0 {
+				fmt.Fprintln(opts.stderr, "Error: no language given")
+				return ExitUsage
+			}
+		default:
+			fmt.Fprintf(opts.stderr, "Error: unknown glossary action %q\n", action)
+			return ExitUsage
+		}
+
+		globify, err := opts.newApp(true)
+		if err != nil {
+			return opts.fail(err)
+		}
+
+		switch action {
+		case "list":
+			glossaries, err := globify.Glossaries(ctx)
+			if err != nil {
+				return opts.fail(err)
+			}
+			w := tabwriter.NewWriter(opts.stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tNAME\tSOURCE\tTARGET\tENTRIES\tREADY")
+			for _, g := range glossaries {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%t\n", g.ID, g.Name, g.SourceLang, g.TargetLang, g.EntryCount, g.Ready)
+			}
+			w.Flush()
+
+		case "create", "update":
+			replace := action == "update"
+			done, replaced := "created", "replaced"
+			if opts.dryRun {
+				done, replaced = "would create", "would replace"
+			}
+			for _, file := range args {
+				result, err := globify.CreateGlossary(ctx, file, *target, replace)
+				if err != nil {
+					return opts.fail(err)
+				}
+				fmt.Fprintf(opts.stdout, "%s: %s glossary '%s' with %d entries from %s\n", result.Language, done, result.Name, result.Entries, result.File)
+				if len(result.Removed) > 0 {
+					fmt.Fprintf(opts.stdout, "%s: %s %s\n", result.Language, replaced, strings.Join(result.Removed, ", "))
+				}
+			}
+
+		case "delete":
+			done := "deleted"
+			if opts.dryRun {
+				done = "would delete"
+			}
+			for _, lang := range args {
+				result, err := globify.DeleteGlossary(ctx, lang)
+				if err != nil {
+					return opts.fail(err)
+				}
+				if len(result.Removed) == 0 {
+					fmt.Fprintf(opts.stdout, "%s: no glossary '%s'\n", result.Language, result.Name)
+					continue
+				}
+				fmt.Fprintf(opts.stdout, "%s: %s glossary '%s' (%s)\n", result.Language, done, result.Name, strings.Join(result.Removed, ", "))
+			}
+		}
+		return ExitOK
+	}
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -1,11 +1,9 @@
 package globify
 
 import (
-	"fmt"
 	"log"
 	"os"
 
-	"github.com/bernardoforcillo/globify/internal/app"
 	"github.com/joho/godotenv"
 )
 
@@ -16,18 +14,9 @@ func Main() {
 		log.Println("No .env file found, relying on environment variables")
 	}
 
-	// Create and run app
-	globify, err := app.NewApp()
-	if err != nil {
-		log.Fatalf("Error initializing application: %v", err)
-	}
-
-	if err := globify.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
 
 func main() {
 	Main()
-}
\ No newline at end of file
+}
@@ -0,0 +1,428 @@
+package globify_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/cmd/globify"
+	"github.com/bernardoforcillo/globify/internal/app"
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
+	"github.com/bernardoforcillo/globify/internal/xliff"
+)
+
+// setupProject writes a configuration and translation files into a temporary directory
+// and returns the path of the configuration file
+func setupProject(t *testing.T) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	translationsDir := filepath.Join(tempDir, "translations")
+
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr", "es"},
+		Folder:          translationsDir,
+	}
+	configFile := filepath.Join(tempDir, config.DefaultFileName)
+	if err := cfg.Save(configFile); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	fm := files.NewJSONManager()
+	contents := map[string]files.LanguageContent{
+		"en": {
+			"greeting": "Hello",
+			"nested": map[string]interface{}{
+				"key1": "Nested value 1",
+			},
+		},
+		"fr": {
+			"greeting": "Bonjour",
+			"obsolete": "Obsolète",
+			"nested": map[string]interface{}{
+				"key1": "Valeur 1",
+			},
+		},
+	}
+	for lang, content := range contents {
+		if err := fm.Write(filepath.Join(translationsDir, lang+".json"), content); err != nil {
+			t.Fatalf("Failed to write %s file: %v", lang, err)
+		}
+	}
+
+	return configFile
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "Help command", args: []string{"help"}, wantCode: globify.ExitOK},
+		{name: "Help flag", args: []string{"-h"}, wantCode: globify.ExitOK},
+		{name: "Command help flag", args: []string{"stats", "-h"}, wantCode: globify.ExitOK},
+		{name: "Unknown command", args: []string{"unknown"}, wantCode: globify.ExitUsage},
+		{name: "Unknown flag", args: []string{"--unknown"}, wantCode: globify.ExitUsage},
+		{name: "Invalid deadline", args: []string{"--deadline", "soon", "stats"}, wantCode: globify.ExitUsage},
+		{name: "Unexpected argument", args: []string{"stats", "extra"}, wantCode: globify.ExitUsage},
+		{name: "Missing config", args: []string{"--config", "missing.json", "stats"}, wantCode: globify.ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := globify.Run(tt.args, nil, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("Run(%v) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	configFile := setupProject(t)
+
+	var stdout, stderr bytes.Buffer
+	code := globify.Run([]string{"--config", configFile, "stats"}, nil, &stdout, &stderr)
+	if code != globify.ExitOK {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, want := range []string{"fr        2     2           0        1      100.0%", "es        2     0           2        0      0.0%"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("stats output %q does not contain %q", output, want)
+		}
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	configFile := setupProject(t)
+
+	var stdout, stderr bytes.Buffer
+	code := globify.Run([]string{"check", "--config", configFile}, nil, &stdout, &stderr)
+	if code != globify.ExitCheckFailed {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitCheckFailed, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "fr: extra key 'obsolete'") {
+		t.Errorf("check output %q does not report the extra key", stdout.String())
+	}
+
+	// Restricting the check to a complete language succeeds once it is pruned
+	stdout.Reset()
+	if code := globify.Run([]string{"--config", configFile, "--lang", "fr", "prune"}, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("prune Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "fr: removed 'obsolete'") {
+		t.Errorf("prune output %q does not report the removed key", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := globify.Run([]string{"--config", configFile, "--lang", "fr", "check"}, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Errorf("check Run() after prune = %d, want %d (output: %s)", code, globify.ExitOK, stdout.String())
+	}
+}
+
+func TestRunCheckJSON(t *testing.T) {
+	configFile := setupProject(t)
+
+	var stdout, stderr bytes.Buffer
+	code := globify.Run([]string{"--config", configFile, "check", "--format", "json"}, nil, &stdout, &stderr)
+	if code != globify.ExitCheckFailed {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitCheckFailed, stderr.String())
+	}
+
+	var report struct {
+		Issues []app.Issue `json:"issues"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("check output is not valid JSON: %v", err)
+	}
+	if len(report.Issues) != 3 {
+		t.Errorf("check reported %d issues, want 3: %+v", len(report.Issues), report.Issues)
+	}
+
+	if code := globify.Run([]string{"--config", configFile, "check", "--format", "xml"}, nil, &stdout, &stderr); code != globify.ExitUsage {
+		t.Errorf("Run() with unsupported format = %d, want %d", code, globify.ExitUsage)
+	}
+}
+
+func TestRunInit(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), config.DefaultFileName)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"--config", configFile, "init", "--yes", "--languages", "fr,de", "--type", "ast-json"}
+	if code := globify.Run(args, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+
+	cfg, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if cfg.TranslationType != "ast-json" || len(cfg.Languages) != 2 {
+		t.Errorf("init wrote %+v", cfg)
+	}
+
+	// An existing configuration is not overwritten without --force
+	if code := globify.Run(args, nil, &stdout, &stderr); code != globify.ExitError {
+		t.Errorf("Run() over existing config = %d, want %d", code, globify.ExitError)
+	}
+
+	// Invalid values are rejected
+	if _, err := os.Stat(configFile); err != nil {
+		t.Fatalf("config file disappeared: %v", err)
+	}
+	invalid := []string{"--config", configFile, "init", "--yes", "--force", "--type", "invalid"}
+	if code := globify.Run(invalid, nil, &stdout, &stderr); code != globify.ExitError {
+		t.Errorf("Run() with invalid type = %d, want %d", code, globify.ExitError)
+	}
+}
+
+func TestRunInitDetection(t *testing.T) {
+	tempDir := t.TempDir()
+	localesDir := filepath.Join(tempDir, "src", "locales")
+
+	fm := files.NewJSONManager()
+	contents := map[string]files.LanguageContent{
+		"en": {"greeting": "Hello, {name}!", "farewell": "Goodbye"},
+		"fr": {"greeting": "Bonjour, {name} !"},
+		"it": {"greeting": "Ciao, {name}!"},
+	}
+	for lang, content := range contents {
+		if err := fm.Write(filepath.Join(localesDir, lang+".json"), content); err != nil {
+			t.Fatalf("Failed to write %s file: %v", lang, err)
+		}
+	}
+	// Files in dependencies are ignored
+	if err := fm.Write(filepath.Join(tempDir, "node_modules", "lib", "de.json"), contents["en"]); err != nil {
+		t.Fatalf("Failed to write dependency file: %v", err)
+	}
+
+	configFile := filepath.Join(tempDir, config.DefaultFileName)
+
+	// Accept every detected value except the target languages
+	stdin := strings.NewReader("\n\n\n\nfr\n")
+	var stdout, stderr bytes.Buffer
+	if code := globify.Run([]string{"--config", configFile, "init"}, stdin, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+
+	cfg, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	want := &config.Config{
+		TranslationType: "ast-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr"},
+		Folder:          localesDir,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("init wrote %+v, want %+v", cfg, want)
+	}
+	if !strings.Contains(stdout.String(), "Detected 3 translation files") {
+		t.Errorf("init output %q does not report the detected files", stdout.String())
+	}
+}
+
+func TestRunInitConfigInSubdirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	fm := files.NewJSONManager()
+	for _, lang := range []string{"en", "fr"} {
+		if err := fm.Write(filepath.Join(tempDir, "app", "locales", lang+".json"), files.LanguageContent{"greeting": "Hello"}); err != nil {
+			t.Fatalf("Failed to write %s file: %v", lang, err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get current directory: %v", err)
+	}
+	defer os.Chdir(cwd)
+	if err := os.Chdir(tempDir); err != nil {
+		t.Fatalf("Failed to change to temp directory: %v", err)
+	}
+
+	// The folder is detected next to the configuration and written relative to the working directory
+	configFile := filepath.Join("app", config.DefaultFileName)
+	var stdout, stderr bytes.Buffer
+	if code := globify.Run([]string{"--config", configFile, "init", "--yes"}, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+	cfg, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if want := filepath.Join("app", "locales"); cfg.Folder != want {
+		t.Errorf("init wrote folder %q, want %q", cfg.Folder, want)
+	}
+
+	// Later commands find the translation files
+	stdout.Reset()
+	if code := globify.Run([]string{"--config", configFile, "stats"}, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("stats Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "fr") {
+		t.Errorf("stats output %q does not list fr", stdout.String())
+	}
+}
+
+func TestRunExportImport(t *testing.T) {
+	configFile := setupProject(t)
+	tempDir := filepath.Dir(configFile)
+
+	cfg, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	cfg.LockFile = filepath.Join(tempDir, "globify.lock")
+	if err := cfg.Save(configFile); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	// Only Spanish has untranslated keys
+	outputDir := filepath.Join(tempDir, "xliff")
+	var stdout, stderr bytes.Buffer
+	args := []string{"--config", configFile, "export", "--xliff-version", "2.0", "--output", outputDir}
+	if code := globify.Run(args, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("export Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+	xliffFile := filepath.Join(outputDir, "es.xlf")
+	if want := "es: exported 2 keys to " + xliffFile + "\n"; stdout.String() != want {
+		t.Errorf("export output = %q, want %q", stdout.String(), want)
+	}
+
+	// The agency only finished the greeting
+	file, err := os.Open(xliffFile)
+	if err != nil {
+		t.Fatalf("Failed to open XLIFF file: %v", err)
+	}
+	doc, err := xliff.Read(file)
+	file.Close()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	for i, unit := range doc.Units {
+		if unit.Key == "greeting" {
+			doc.Units[i].Target = "Hola"
+			doc.Units[i].State = xliff.StateReviewed
+		}
+	}
+	buffer := &bytes.Buffer{}
+	if err := xliff.Write(buffer, doc); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := os.WriteFile(xliffFile, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write XLIFF file: %v", err)
+	}
+
+	stdout.Reset()
+	if code := globify.Run([]string{"--config", configFile, "import", xliffFile}, nil, &stdout, &stderr); code != globify.ExitOK {
+		t.Fatalf("import Run() = %d, want %d (stderr: %s)", code, globify.ExitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "es: imported 1 keys") || !strings.Contains(stdout.String(), "es: skipped 1 ") {
+		t.Errorf("import output = %q", stdout.String())
+	}
+
+	es, err := files.NewJSONManager().Read(filepath.Join(tempDir, "translations", "es.json"))
+	if err != nil {
+		t.Fatalf("Failed to read es file: %v", err)
+	}
+	if !reflect.DeepEqual(es, files.LanguageContent{"greeting": "Hola"}) {
+		t.Errorf("import wrote %v", es)
+	}
+
+	translationLock, err := lock.Load(cfg.LockFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if entry := translationLock.Languages["es"]["greeting"]; !entry.Approved {
+		t.Errorf("import did not approve the greeting: %+v", entry)
+	}
+
+	// Imports need a file
+	if code := globify.Run([]string{"--config", configFile, "import"}, nil, &stdout, &stderr); code != globify.ExitUsage {
+		t.Errorf("import Run() without file = %d, want %d", code, globify.ExitUsage)
+	}
+}
+
+func TestRunGlossary(t *testing.T) {
+	configFile := setupProject(t)
+
+	// A fake DeepL API keeping glossaries in memory
+	var glossaries []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": glossaries})
+		case http.MethodPost:
+			var request map[string]string
+			json.NewDecoder(r.Body).Decode(&request)
+			glossary := map[string]interface{}{
+				"glossary_id": fmt.Sprintf("g%d", len(glossaries)+1),
+				"name":        request["name"],
+				"ready":       true,
+				"source_lang": request["source_lang"],
+				"target_lang": request["target_lang"],
+				"entry_count": strings.Count(request["entries"], "\n"),
+			}
+			glossaries = append(glossaries, glossary)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(glossary)
+		case http.MethodDelete:
+			for i, glossary := range glossaries {
+				if "/v2/glossaries/"+glossary["glossary_id"].(string) == r.URL.Path {
+					glossaries = append(glossaries[:i], glossaries[i+1:]...)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("DEEPL_API_KEY", "secret")
+	t.Setenv("DEEPL_BASE_URL", server.URL)
+
+	glossaryFile := filepath.Join(t.TempDir(), "fr.csv")
+	if err := os.WriteFile(glossaryFile, []byte("Globify,Globify\nsign in,se connecter\n"), 0644); err != nil {
+		t.Fatalf("Failed to write glossary file: %v", err)
+	}
+
+	steps := []struct {
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{[]string{"glossary", "create", glossaryFile}, globify.ExitOK, "fr: created glossary 'globify' with 2 entries"},
+		{[]string{"glossary", "create", glossaryFile}, globify.ExitError, ""},
+		{[]string{"glossary", "update", glossaryFile}, globify.ExitOK, "fr: replaced g1"},
+		{[]string{"glossary", "list"}, globify.ExitOK, "g2  globify  en      fr      2        true"},
+		{[]string{"glossary", "create", "--target", "de", glossaryFile}, globify.ExitError, ""},
+		{[]string{"glossary", "delete", "fr"}, globify.ExitOK, "fr: deleted glossary 'globify' (g2)"},
+		{[]string{"glossary", "delete", "fr"}, globify.ExitOK, "fr: no glossary 'globify'"},
+		{[]string{"glossary", "rename"}, globify.ExitUsage, ""},
+	}
+	for _, step := range steps {
+		var stdout, stderr bytes.Buffer
+		code := globify.Run(append([]string{"--config", configFile}, step.args...), nil, &stdout, &stderr)
+		if code != step.wantCode {
+			t.Fatalf("Run(%v) = %d, want %d (stderr: %s)", step.args, code, step.wantCode, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), step.want) {
+			t.Errorf("Run(%v) output %q does not contain %q", step.args, stdout.String(), step.want)
+		}
+	}
+}
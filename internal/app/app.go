@@ -1,22 +1,31 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"path/filepath"
 
 	"github.com/bernardoforcillo/globify/internal/config"
 	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
 	"github.com/bernardoforcillo/globify/internal/processor"
 	"github.com/bernardoforcillo/globify/internal/translator"
 )
 
 // App coordinates the translation process
 type App struct {
-	config     *config.Config
-	translator translator.Translator
+	config      *config.Config
+	translator  translator.Translator
 	fileManager files.FileManager
-	processor  processor.ObjectProcessor
+	processor   processor.ObjectProcessor
+	lockFile    string
+	verbose     bool
+	dryRun      bool
+	ignoreQuota bool
+	output      io.Writer
 }
 
 // NewApp creates and initializes a new App instance
@@ -27,8 +36,13 @@ func NewApp() (*App, error) {
 		return nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	return NewAppWithConfig(cfg)
+}
+
+// NewAppWithConfig creates a new App instance for an already loaded configuration
+func NewAppWithConfig(cfg *config.Config) (*App, error) {
 	// Create translator
-	trans, err := translator.CreateTranslator()
+	trans, err := translator.CreateTranslator(cfg.Provider, cfg.TargetLanguages())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create translator: %w", err)
 	}
@@ -45,77 +59,269 @@ func NewApp() (*App, error) {
 		return nil, fmt.Errorf("failed to create processor: %w", err)
 	}
 
+	return NewAppWithDependencies(cfg, trans, fm, proc), nil
+}
+
+// NewAppWithDependencies creates an App from already constructed dependencies.
+// The translator and processor may be nil when the App is only used for
+// commands that do not translate, like Stats or Prune.
+func NewAppWithDependencies(
+	cfg *config.Config,
+	trans translator.Translator,
+	fm files.FileManager,
+	proc processor.ObjectProcessor,
+) *App {
+	lockFile := cfg.LockFile
+	if lockFile == "" {
+		lockFile = lock.DefaultFileName
+	}
+
 	return &App{
-		config:     cfg,
-		translator: trans,
+		config:      cfg,
+		translator:  trans,
 		fileManager: fm,
-		processor:  proc,
-	}, nil
+		processor:   proc,
+		lockFile:    lockFile,
+		output:      os.Stdout,
+	}
 }
 
-// Run performs the translation process
-func (a *App) Run() error {
-	log.Printf("Starting translation from %s to %v", a.config.BaseLanguage, a.config.Languages)
-	
+// SetVerbose enables logging of every step of the translation process
+func (a *App) SetVerbose(verbose bool) {
+	a.verbose = verbose
+}
+
+// SetDryRun makes Run print the translation plan instead of translating,
+// and Prune, Export and Import report what they would do without writing
+// any file
+func (a *App) SetDryRun(dryRun bool) {
+	a.dryRun = dryRun
+}
+
+// SetOutput sets where reports like the dry-run plan are written, defaults to stdout
+func (a *App) SetOutput(w io.Writer) {
+	a.output = w
+}
+
+// logf logs progress details only in verbose mode
+func (a *App) logf(format string, args ...interface{}) {
+	if a.verbose {
+		log.Printf(format, args...)
+	}
+}
+
+// filePath returns the path of the translation file of a language
+func (a *App) filePath(lang string) string {
+	if resolver, ok := a.fileManager.(files.PathResolver); ok {
+		return resolver.FilePath(a.config.Folder, lang, lang == a.config.BaseLanguage)
+	}
+	return filepath.Join(a.config.Folder, fmt.Sprintf("%s.%s", lang, a.config.FileExtension))
+}
+
+// readLanguage reads the content of lang from its translation file
+func (a *App) readLanguage(lang string) (files.LanguageContent, error) {
+	doc, err := a.readLanguageDocument(lang)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// readLanguageDocument reads the content of lang from its translation file
+// along with the order of its keys. Files holding several languages keep
+// their own order, so their documents have none.
+func (a *App) readLanguageDocument(lang string) (*files.Document, error) {
+	if multi, ok := a.fileManager.(files.MultiLanguageManager); ok {
+		content, err := multi.ReadLanguage(a.filePath(lang), lang)
+		if err != nil {
+			return nil, err
+		}
+		return &files.Document{Content: content}, nil
+	}
+	return a.fileManager.ReadDocument(a.filePath(lang))
+}
+
+// writeLanguage writes the content of lang to its translation file with
+// its keys in the given order, usually the one of the base language file
+func (a *App) writeLanguage(lang string, content files.LanguageContent, order *files.KeyOrder) error {
+	if multi, ok := a.fileManager.(files.MultiLanguageManager); ok {
+		return multi.WriteLanguage(a.filePath(lang), lang, content)
+	}
+	return a.fileManager.WriteDocument(a.filePath(lang), &files.Document{Content: content, Order: order})
+}
+
+// targetLanguages returns the configured languages without the base language
+func (a *App) targetLanguages() []string {
+	return a.config.TargetLanguages()
+}
+
+// readBase reads the base language file
+func (a *App) readBase() (files.LanguageContent, error) {
+	baseDoc, err := a.readBaseDocument()
+	if err != nil {
+		return nil, err
+	}
+	return baseDoc.Content, nil
+}
+
+// readBaseDocument reads the base language file along with the order of its
+// keys, which target files are written in
+func (a *App) readBaseDocument() (*files.Document, error) {
+	baseFilePath := a.filePath(a.config.BaseLanguage)
+	a.logf("Reading base language file: %s", baseFilePath)
+
+	baseDoc, err := a.readLanguageDocument(a.config.BaseLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base language file: %w", err)
+	}
+	return baseDoc, nil
+}
+
+// languageBase returns the base content with its messages shaped like those
+// of lang, like gettext plurals with the number of forms of the language
+func (a *App) languageBase(lang string, base files.LanguageContent) files.LanguageContent {
+	if shaper, ok := a.fileManager.(files.ContentShaper); ok {
+		return shaper.ShapeContent(a.filePath(lang), lang, base)
+	}
+	return base
+}
+
+// readTarget reads the translation file of a target language, returning
+// empty content if it does not exist yet
+func (a *App) readTarget(lang string) (files.LanguageContent, bool, error) {
+	targetFilePath := a.filePath(lang)
+
+	exists, err := a.fileManager.Exists(targetFilePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return make(files.LanguageContent), false, nil
+	}
+
+	content, err := a.readLanguage(lang)
+	if err != nil {
+		return nil, true, err
+	}
+	return content, true, nil
+}
+
+// Run performs the translation process. When ctx is cancelled or the
+// translator fails for good, the keys translated so far are written and
+// locked, and the others stay pending for the next run.
+func (a *App) Run(ctx context.Context) error {
+	if a.dryRun {
+		plans, err := a.Plan()
+		if err != nil {
+			return err
+		}
+		WritePlan(a.output, plans, a.verbose)
+		return nil
+	}
+
+	log.Printf("Starting translation from %s to %v", a.config.BaseLanguage, a.targetLanguages())
+
+	// Stop before writing anything when the run would exceed the provider quota
+	if err := a.preflightQuota(ctx); err != nil {
+		return err
+	}
+	defer a.reportQuota(ctx)
+
 	// Read the base language file
-	baseFilePath := filepath.Join(a.config.Folder, fmt.Sprintf("%s.%s", a.config.BaseLanguage, a.config.FileExtension))
-	log.Printf("Reading base language file: %s", baseFilePath)
-	
-	baseContent, err := a.fileManager.Read(baseFilePath)
+	baseDoc, err := a.readBaseDocument()
 	if err != nil {
-		return fmt.Errorf("failed to read base language file: %w", err)
+		return err
 	}
-	
+
+	// Load the fingerprints of the sources previous translations were made from
+	translationLock, err := lock.Load(a.lockFile)
+	if err != nil {
+		return err
+	}
+
 	// Process each language sequentially instead of concurrently
-	for _, lang := range a.config.Languages {
-		// Skip if target language is the same as base language
-		if lang == a.config.BaseLanguage {
-			continue
+	for _, lang := range a.targetLanguages() {
+		if ctx.Err() != nil {
+			return fmt.Errorf("translation interrupted before %s: %w", lang, ctx.Err())
 		}
-		
 		log.Printf("Translating from %s to %s", a.config.BaseLanguage, lang)
 		
 		// Path for the target language file
-		targetFilePath := filepath.Join(a.config.Folder, fmt.Sprintf("%s.%s", lang, a.config.FileExtension))
+		targetFilePath := a.filePath(lang)
 		
-		// Check if target file already exists
-		var previousContent files.LanguageContent
-		exists, fileErr := a.fileManager.Exists(targetFilePath)
+		// Use existing translations as baseline
+		previousContent, exists, fileErr := a.readTarget(lang)
 		if fileErr != nil {
-			log.Printf("Warning: Error checking existence of %s: %v", targetFilePath, fileErr)
-		}
-		
-		if exists {
-			log.Printf("Target file %s already exists, using existing translations as baseline", targetFilePath)
-			previousContent, fileErr = a.fileManager.Read(targetFilePath)
-			if fileErr != nil {
-				log.Printf("Warning: Failed to read existing target file %s: %v", targetFilePath, fileErr)
-				previousContent = make(files.LanguageContent)
-			}
-		} else {
+			log.Printf("Warning: Failed to read existing target file %s: %v", targetFilePath, fileErr)
 			previousContent = make(files.LanguageContent)
+		} else if exists {
+			a.logf("Target file %s already exists, using existing translations as baseline", targetFilePath)
 		}
 		
+		baseContent := a.languageBase(lang, baseDoc.Content)
+
+		// Only translate keys whose source changed since they were last translated
+		pendingContent := translationLock.Pending(lang, baseContent, previousContent)
+
 		// Process translations
-		translatedContent, procErr := a.processor.Execute(
-			baseContent,
+		processedDoc, failedPaths, procErr := processor.ExecuteDocument(
+			ctx,
+			a.processor,
+			&files.Document{Content: pendingContent, Order: baseDoc.Order},
 			a.config.BaseLanguage,
 			lang,
-			previousContent,
+			make(files.LanguageContent),
 		)
-		if procErr != nil {
+		// Cancelled runs and fatal translator errors, like a used up quota,
+		// stop the processor with the keys it finished
+		interrupted := procErr != nil && processedDoc != nil
+		if procErr != nil && !interrupted {
 			return fmt.Errorf("failed to translate to %s: %w", lang, procErr)
 		}
+
+		translatedContent := lock.Merge(baseContent, previousContent, processedDoc.Content)
 		
-		// Write translated content to file
-		log.Printf("Writing translated content to %s", targetFilePath)
-		if writeErr := a.fileManager.Write(targetFilePath, translatedContent); writeErr != nil {
+		// Write translated content to file in the key order of the base file
+		a.logf("Writing translated content to %s", targetFilePath)
+		if writeErr := a.writeLanguage(lang, translatedContent, processedDoc.Order); writeErr != nil {
 			return fmt.Errorf("failed to write translated file %s: %w", targetFilePath, writeErr)
 		}
-		
+
+		// Record the sources right away so finished languages are not translated again
+		previousEntries := translationLock.Languages[lang]
+		translationLock.Update(lang, baseContent, translatedContent)
+		// Strings that failed to translate, and the keys an interrupted run did
+		// not get to, keep their previous lock entries so they stay pending
+		if interrupted {
+			failedPaths = append(failedPaths, unfinishedPaths(pendingContent, processedDoc.Content)...)
+		}
+		translationLock.Restore(lang, previousEntries, failedPaths)
+		if saveErr := translationLock.Save(a.lockFile); saveErr != nil {
+			return saveErr
+		}
+
+		if interrupted {
+			return fmt.Errorf("translation to %s interrupted, the finished translations were saved: %w", lang, procErr)
+		}
 		log.Printf("Successfully translated to %s", lang)
 	}
 	
 	log.Printf("Translation process completed successfully")
 	return nil
-}
\ No newline at end of file
+}
+
+// unfinishedPaths returns the paths of the pending strings missing from the
+// processed content of an interrupted run
+func unfinishedPaths(pending, processed files.LanguageContent) []string {
+	done := files.Flatten(processed)
+	var paths []string
+	for path, value := range files.Flatten(pending) {
+		if _, ok := value.(string); !ok {
+			continue
+		}
+		if _, ok := done[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
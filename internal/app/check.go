@@ -0,0 +1,196 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/icu"
+	"github.com/bernardoforcillo/globify/internal/lock"
+)
+
+// IssueKind identifies the kind of problem found in a target language file
+type IssueKind string
+
+const (
+	// MissingKey is reported for base keys absent from the target
+	MissingKey IssueKind = "missing"
+	// ExtraKey is reported for target keys absent from the base
+	ExtraKey IssueKind = "extra"
+	// StaleKey is reported for translations whose base string changed since they were made
+	StaleKey IssueKind = "stale"
+	// TypeMismatch is reported when a key is a string on one side and a nested object on the other
+	TypeMismatch IssueKind = "type"
+	// PlaceholderMismatch is reported when a translation does not use the ICU placeholders of its source
+	PlaceholderMismatch IssueKind = "placeholder"
+)
+
+// Issue describes a problem found in a target language file
+type Issue struct {
+	Language string    `json:"language"`
+	File     string    `json:"file"`
+	Key      string    `json:"key"`
+	Kind     IssueKind `json:"kind"`
+	Message  string    `json:"message"`
+}
+
+// Check compares every target language file with the base language file without modifying anything
+func (a *App) Check() ([]Issue, error) {
+	baseContent, err := a.readBase()
+	if err != nil {
+		return nil, err
+	}
+
+	translationLock, err := lock.Load(a.lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	for _, lang := range a.targetLanguages() {
+		targetFilePath := a.filePath(lang)
+
+		targetContent, _, err := a.readTarget(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target file %s: %w", targetFilePath, err)
+		}
+
+		checker := &checker{
+			language: lang,
+			file:     targetFilePath,
+			entries:  translationLock.Languages[lang],
+		}
+		checker.compare(a.languageBase(lang, baseContent), targetContent, "")
+
+		sort.SliceStable(checker.issues, func(i, j int) bool {
+			return checker.issues[i].Key < checker.issues[j].Key
+		})
+		issues = append(issues, checker.issues...)
+	}
+
+	return issues, nil
+}
+
+// checker collects the issues of a single target language file
+type checker struct {
+	language string
+	file     string
+	entries  map[string]lock.Entry
+	issues   []Issue
+}
+
+func (c *checker) report(key string, kind IssueKind, format string, args ...interface{}) {
+	c.issues = append(c.issues, Issue{
+		Language: c.language,
+		File:     c.file,
+		Key:      key,
+		Kind:     kind,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// compare walks base and target side by side
+func (c *checker) compare(base, target files.LanguageContent, prefix string) {
+	for key, baseValue := range base {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+		targetValue, exists := target[key]
+		if !exists {
+			if nested, ok := files.AsContent(baseValue); ok {
+				for nestedKey := range files.Flatten(nested) {
+					c.report(files.JoinPath(path, nestedKey), MissingKey, "key is missing from the translation")
+				}
+			} else {
+				c.report(path, MissingKey, "key is missing from the translation")
+			}
+			continue
+		}
+
+		baseNested, baseIsObject := files.AsContent(baseValue)
+		targetNested, targetIsObject := files.AsContent(targetValue)
+		switch {
+		case baseIsObject && targetIsObject:
+			c.compare(baseNested, targetNested, path)
+
+		case baseIsObject:
+			c.report(path, TypeMismatch, "key is a nested object in the base language but a %s in the translation", describeType(targetValue))
+
+		case targetIsObject:
+			c.report(path, TypeMismatch, "key is a %s in the base language but a nested object in the translation", describeType(baseValue))
+
+		default:
+			// Array items are compared by index
+			baseItems, baseIsArray := files.ArrayContent(baseValue)
+			targetItems, targetIsArray := files.ArrayContent(targetValue)
+			if baseIsArray && targetIsArray {
+				c.compare(baseItems, targetItems, path)
+				continue
+			}
+
+			baseString, ok := baseValue.(string)
+			if !ok {
+				continue
+			}
+			targetString, ok := targetValue.(string)
+			if !ok {
+				c.report(path, TypeMismatch, "key is a string in the base language but a %s in the translation", describeType(targetValue))
+				continue
+			}
+			c.compareStrings(path, baseString, targetString)
+		}
+	}
+
+	for key, targetValue := range target {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+		if _, exists := base[key]; exists {
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+		if nested, ok := files.AsContent(targetValue); ok {
+			for nestedKey := range files.Flatten(nested) {
+				c.report(files.JoinPath(path, nestedKey), ExtraKey, "key does not exist in the base language")
+			}
+		} else {
+			c.report(path, ExtraKey, "key does not exist in the base language")
+		}
+	}
+}
+
+// compareStrings checks a translation against its source string
+func (c *checker) compareStrings(path, source, translation string) {
+	if entry, ok := c.entries[path]; ok && entry.Hash != lock.Hash(source) {
+		c.report(path, StaleKey, "base language string changed since it was translated")
+	}
+
+	// Placeholders can only be compared for valid messages
+	if err := icu.ComparePlaceholders(source, translation); err != nil {
+		c.report(path, PlaceholderMismatch, "%v", err)
+	}
+}
+
+// describeType names the JSON type of a value for issue messages
+func describeType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64, float32, int, int64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		if _, ok := files.AsContent(value); ok {
+			return "nested object"
+		}
+		return fmt.Sprintf("%T", value)
+	}
+}
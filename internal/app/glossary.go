@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+// GlossaryResult describes the DeepL glossary created, replaced or deleted
+// for a target language
+type GlossaryResult struct {
+	Language string `json:"language"`
+	File     string `json:"file,omitempty"`
+	Name     string `json:"name"`
+	// ID is the id of the created glossary, empty for deletions and dry runs
+	ID      string `json:"id,omitempty"`
+	Entries int    `json:"entries"`
+	// Removed lists the ids of the glossaries replaced or deleted
+	Removed []string `json:"removed"`
+}
+
+// GlossaryLanguage returns the target language of a glossary file named
+// after it, like "de" for glossaries/de.csv
+func GlossaryLanguage(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+// Glossaries returns the glossaries of the DeepL accounts used by the
+// target languages
+func (a *App) Glossaries(ctx context.Context) ([]translator.Glossary, error) {
+	var glossaries []translator.Glossary
+	listed := make(map[*translator.DeeplTranslator]bool)
+	seen := make(map[string]bool)
+	for _, lang := range a.targetLanguages() {
+		deepl, ok := translator.ForLanguage(a.translator, lang).(*translator.DeeplTranslator)
+		if !ok || listed[deepl] {
+			continue
+		}
+		listed[deepl] = true
+
+		list, err := deepl.ListGlossaries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list glossaries: %w", err)
+		}
+		for _, glossary := range list {
+			if !seen[glossary.ID] {
+				seen[glossary.ID] = true
+				glossaries = append(glossaries, glossary)
+			}
+		}
+	}
+	if len(listed) == 0 {
+		return nil, fmt.Errorf("glossaries need the deepl provider, which no target language uses")
+	}
+
+	sort.Slice(glossaries, func(i, j int) bool {
+		if glossaries[i].Name != glossaries[j].Name {
+			return glossaries[i].Name < glossaries[j].Name
+		}
+		return glossaries[i].TargetLang < glossaries[j].TargetLang
+	})
+	return glossaries, nil
+}
+
+// CreateGlossary creates the DeepL glossary of a target language from a
+// CSV or TSV file, for translations from the base language. An empty lang
+// is taken from the file name. Existing glossaries of the language pair
+// are only replaced when replace is set. Nothing is changed in dry-run mode.
+func (a *App) CreateGlossary(ctx context.Context, file, lang string, replace bool) (*GlossaryResult, error) {
+	if lang == "" {
+		lang = GlossaryLanguage(file)
+	}
+	deepl, err := a.glossaryTranslator(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := translator.ReadGlossaryFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	glossaries, err := deepl.ListGlossaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossaries: %w", err)
+	}
+	existing := translator.FindGlossaries(glossaries, deepl.GlossaryName(), a.config.BaseLanguage, lang)
+	if len(existing) > 0 && !replace {
+		return nil, fmt.Errorf("glossary '%s' already exists for %s, use update to replace it", deepl.GlossaryName(), lang)
+	}
+
+	result := &GlossaryResult{Language: lang, File: file, Name: deepl.GlossaryName(), Entries: len(entries)}
+	for _, glossary := range existing {
+		result.Removed = append(result.Removed, glossary.ID)
+	}
+	if a.dryRun {
+		return result, nil
+	}
+
+	// Create the new glossary first so translations never run without one
+	glossary, err := deepl.CreateGlossary(ctx, deepl.GlossaryName(), a.config.BaseLanguage, lang, entries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create glossary for %s: %w", lang, err)
+	}
+	result.ID = glossary.ID
+	a.logf("Created glossary %s for %s with %d entries", glossary.ID, lang, glossary.EntryCount)
+
+	for _, old := range existing {
+		if err := deepl.DeleteGlossary(ctx, old.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete replaced glossary %s: %w", old.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// DeleteGlossary deletes the DeepL glossaries of a target language. Nothing
+// is deleted in dry-run mode.
+func (a *App) DeleteGlossary(ctx context.Context, lang string) (*GlossaryResult, error) {
+	deepl, err := a.glossaryTranslator(lang)
+	if err != nil {
+		return nil, err
+	}
+
+	glossaries, err := deepl.ListGlossaries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list glossaries: %w", err)
+	}
+
+	result := &GlossaryResult{Language: lang, Name: deepl.GlossaryName()}
+	for _, glossary := range translator.FindGlossaries(glossaries, deepl.GlossaryName(), a.config.BaseLanguage, lang) {
+		if !a.dryRun {
+			if err := deepl.DeleteGlossary(ctx, glossary.ID); err != nil {
+				return nil, fmt.Errorf("failed to delete glossary %s: %w", glossary.ID, err)
+			}
+		}
+		result.Removed = append(result.Removed, glossary.ID)
+		result.Entries += glossary.EntryCount
+	}
+	return result, nil
+}
+
+// glossaryTranslator returns the DeepL translator of a target language
+func (a *App) glossaryTranslator(lang string) (*translator.DeeplTranslator, error) {
+	if lang == a.config.BaseLanguage || !contains(a.config.Languages, lang) {
+		return nil, fmt.Errorf("language '%s' is not a configured target language", lang)
+	}
+	deepl, ok := translator.ForLanguage(a.translator, lang).(*translator.DeeplTranslator)
+	if !ok {
+		return nil, fmt.Errorf("glossaries need the deepl provider, which %s does not use", lang)
+	}
+	return deepl, nil
+}
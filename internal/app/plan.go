@@ -0,0 +1,106 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
+	"github.com/bernardoforcillo/globify/internal/processor"
+)
+
+// LanguagePlan describes what a translation run would do for a target language
+type LanguagePlan struct {
+	Language string `json:"language"`
+	// Translate lists the keys that would be sent to the translator
+	Translate []string `json:"translate"`
+	// Reuse lists the keys whose existing translation would be kept
+	Reuse []string `json:"reuse"`
+	// Drop lists the keys of the target file that no longer exist in the base file
+	Drop []string `json:"drop"`
+	// Characters is the number of characters the translator would bill
+	Characters int `json:"characters"`
+}
+
+// Plan computes what Run would do for every target language without
+// translating or writing anything
+func (a *App) Plan() ([]LanguagePlan, error) {
+	baseContent, err := a.readBase()
+	if err != nil {
+		return nil, err
+	}
+
+	translationLock, err := lock.Load(a.lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var plans []LanguagePlan
+	for _, lang := range a.targetLanguages() {
+		previousContent, _, err := a.readTarget(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target file %s: %w", a.filePath(lang), err)
+		}
+
+		langBase := a.languageBase(lang, baseContent)
+		baseKeys := files.Flatten(langBase)
+		pendingContent := translationLock.Pending(lang, langBase, previousContent)
+		pendingKeys := files.Flatten(pendingContent)
+
+		plan := LanguagePlan{
+			Language:   lang,
+			Characters: processor.EstimateCharacters(a.processor, pendingContent),
+		}
+		for key, value := range baseKeys {
+			if _, ok := value.(string); !ok {
+				continue
+			}
+			if _, ok := pendingKeys[key]; ok {
+				plan.Translate = append(plan.Translate, key)
+			} else {
+				plan.Reuse = append(plan.Reuse, key)
+			}
+		}
+		for key := range files.Flatten(previousContent) {
+			if _, ok := baseKeys[key]; !ok {
+				plan.Drop = append(plan.Drop, key)
+			}
+		}
+
+		sort.Strings(plan.Translate)
+		sort.Strings(plan.Reuse)
+		sort.Strings(plan.Drop)
+		plans = append(plans, plan)
+	}
+
+	return plans, nil
+}
+
+// WritePlan prints a human readable translation plan. Reused keys are only
+// listed when verbose is set.
+func WritePlan(w io.Writer, plans []LanguagePlan, verbose bool) {
+	totalKeys, totalCharacters := 0, 0
+
+	for _, plan := range plans {
+		fmt.Fprintf(w, "%s: %d to translate, %d reused, %d dropped, %d characters\n",
+			plan.Language, len(plan.Translate), len(plan.Reuse), len(plan.Drop), plan.Characters)
+
+		for _, key := range plan.Translate {
+			fmt.Fprintf(w, "  translate %s\n", key)
+		}
+		if verbose {
+			for _, key := range plan.Reuse {
+				fmt.Fprintf(w, "  reuse     %s\n", key)
+			}
+		}
+		for _, key := range plan.Drop {
+			fmt.Fprintf(w, "  drop      %s\n", key)
+		}
+
+		totalKeys += len(plan.Translate)
+		totalCharacters += plan.Characters
+	}
+
+	fmt.Fprintf(w, "Total: %d keys to translate, %d characters\n", totalKeys, totalCharacters)
+}
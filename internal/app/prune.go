@@ -0,0 +1,78 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+// PruneResult lists the keys removed from a target language file
+type PruneResult struct {
+	Language string   `json:"language"`
+	Removed  []string `json:"removed"`
+}
+
+// Prune removes from every target language file the keys that no longer exist
+// in the base language file. In dry-run mode no file is written.
+func (a *App) Prune() ([]PruneResult, error) {
+	baseDoc, err := a.readBaseDocument()
+	if err != nil {
+		return nil, err
+	}
+	baseContent := baseDoc.Content
+
+	var results []PruneResult
+	for _, lang := range a.targetLanguages() {
+		targetFilePath := a.filePath(lang)
+
+		targetContent, exists, err := a.readTarget(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target file %s: %w", targetFilePath, err)
+		}
+		if !exists {
+			continue
+		}
+
+		removed := pruneContent(a.languageBase(lang, baseContent), targetContent, "")
+		if len(removed) == 0 {
+			continue
+		}
+		sort.Strings(removed)
+
+		if !a.dryRun {
+			a.logf("Removing %d keys from %s", len(removed), targetFilePath)
+			if err := a.writeLanguage(lang, targetContent, baseDoc.Order); err != nil {
+				return nil, fmt.Errorf("failed to write pruned file %s: %w", targetFilePath, err)
+			}
+		}
+
+		results = append(results, PruneResult{Language: lang, Removed: removed})
+	}
+
+	return results, nil
+}
+
+// pruneContent deletes the keys of target that are not in base and returns their paths
+func pruneContent(base, target files.LanguageContent, prefix string) []string {
+	var removed []string
+
+	for key, value := range target {
+		path := files.KeyPath(prefix, key)
+
+		baseValue, ok := base[key]
+		if !ok {
+			delete(target, key)
+			removed = append(removed, path)
+			continue
+		}
+
+		nested, isObject := files.AsContent(value)
+		baseNested, baseIsObject := files.AsContent(baseValue)
+		if isObject && baseIsObject {
+			removed = append(removed, pruneContent(baseNested, nested, path)...)
+		}
+	}
+
+	return removed
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+// reportTimeout bounds the quota report of runs, which is sent even when
+// the run was cancelled
+const reportTimeout = 10 * time.Second
+
+// QuotaCheck compares the characters a run would send to a provider with
+// the quota left in its account
+type QuotaCheck struct {
+	Provider  string   `json:"provider"`
+	Languages []string `json:"languages"`
+	// Characters is the estimate of the characters the run would send
+	Characters int `json:"characters"`
+	// Remaining is the number of characters left, -1 when the account has no limit
+	Remaining int64 `json:"remaining"`
+}
+
+// Exceeded reports whether the run would need more characters than remain
+func (c QuotaCheck) Exceeded() bool {
+	return c.Remaining >= 0 && int64(c.Characters) > c.Remaining
+}
+
+// CheckQuota compares the estimate of what Run would send with the quota
+// of every provider able to report it. Languages whose provider cannot
+// report its quota are not checked.
+func (a *App) CheckQuota(ctx context.Context) ([]QuotaCheck, error) {
+	reporters, languages := a.usageReporters()
+	if len(reporters) == 0 {
+		return nil, nil
+	}
+
+	plans, err := a.Plan()
+	if err != nil {
+		return nil, err
+	}
+	characters := make(map[string]int, len(plans))
+	for _, plan := range plans {
+		characters[plan.Language] = plan.Characters
+	}
+
+	var checks []QuotaCheck
+	for _, reporter := range reporters {
+		usage, err := reporter.Usage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get translation quota: %w", err)
+		}
+
+		check := QuotaCheck{Provider: usage.Provider, Languages: languages[reporter], Remaining: usage.Remaining()}
+		for _, lang := range check.Languages {
+			check.Characters += characters[lang]
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// SetIgnoreQuota makes Run translate even when the estimate of the run
+// exceeds the quota left in the provider account
+func (a *App) SetIgnoreQuota(ignoreQuota bool) {
+	a.ignoreQuota = ignoreQuota
+}
+
+// preflightQuota stops runs that would exceed the quota of a provider
+// before any file is written, unless the quota is ignored
+func (a *App) preflightQuota(ctx context.Context) error {
+	checks, err := a.CheckQuota(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("Warning: Skipping the quota check: %v", err)
+		return nil
+	}
+
+	for _, check := range checks {
+		a.logf("%s needs %d characters for %v, %d remaining", check.Provider, check.Characters, check.Languages, check.Remaining)
+		if !check.Exceeded() {
+			continue
+		}
+		if a.ignoreQuota {
+			log.Printf("Warning: %s needs %d characters but only %d remain in the quota, translating anyway", check.Provider, check.Characters, check.Remaining)
+			continue
+		}
+		return fmt.Errorf("translation needs %d characters but only %d remain in the %s quota, translate fewer languages with --lang or use --ignore-quota",
+			check.Characters, check.Remaining, check.Provider)
+	}
+	return nil
+}
+
+// reportQuota logs the quota left in the account of every provider, also
+// after ctx was cancelled
+func (a *App) reportQuota(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reportTimeout)
+	defer cancel()
+
+	reporters, _ := a.usageReporters()
+	for _, reporter := range reporters {
+		usage, err := reporter.Usage(ctx)
+		if err != nil {
+			log.Printf("Warning: Failed to get translation quota: %v", err)
+			continue
+		}
+		if usage.Limit == 0 {
+			log.Printf("%s usage: %d characters this period", usage.Provider, usage.Characters)
+			continue
+		}
+		log.Printf("%s usage: %d of %d characters, %d remaining", usage.Provider, usage.Characters, usage.Limit, usage.Remaining())
+	}
+}
+
+// usageReporters returns the translators of the target languages that can
+// report their quota, along with the languages each of them translates
+func (a *App) usageReporters() ([]translator.UsageReporter, map[translator.UsageReporter][]string) {
+	var reporters []translator.UsageReporter
+	languages := make(map[translator.UsageReporter][]string)
+	for _, lang := range a.targetLanguages() {
+		reporter, ok := translator.ForLanguage(a.translator, lang).(translator.UsageReporter)
+		if !ok {
+			continue
+		}
+		if _, seen := languages[reporter]; !seen {
+			reporters = append(reporters, reporter)
+		}
+		languages[reporter] = append(languages[reporter], lang)
+	}
+	return reporters, languages
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+// LanguageStats summarizes the translation progress of a target language
+type LanguageStats struct {
+	Language   string `json:"language"`
+	Keys       int    `json:"keys"`
+	Translated int    `json:"translated"`
+	Missing    int    `json:"missing"`
+	Extra      int    `json:"extra"`
+}
+
+// Progress returns the percentage of base keys present in the target language
+func (s LanguageStats) Progress() float64 {
+	if s.Keys == 0 {
+		return 100
+	}
+	return float64(s.Translated) / float64(s.Keys) * 100
+}
+
+// Stats computes the translation progress of every target language
+func (a *App) Stats() ([]LanguageStats, error) {
+	baseContent, err := a.readBase()
+	if err != nil {
+		return nil, err
+	}
+	var stats []LanguageStats
+	for _, lang := range a.targetLanguages() {
+		targetContent, _, err := a.readTarget(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target file %s: %w", a.filePath(lang), err)
+		}
+		baseKeys := files.Flatten(a.languageBase(lang, baseContent))
+		targetKeys := files.Flatten(targetContent)
+
+		langStats := LanguageStats{Language: lang, Keys: len(baseKeys)}
+		for key := range baseKeys {
+			if _, ok := targetKeys[key]; ok {
+				langStats.Translated++
+			} else {
+				langStats.Missing++
+			}
+		}
+		for key := range targetKeys {
+			if _, ok := baseKeys[key]; !ok {
+				langStats.Extra++
+			}
+		}
+
+		stats = append(stats, langStats)
+	}
+
+	return stats, nil
+}
@@ -1,13 +1,25 @@
 package app_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/bernardoforcillo/globify/internal/app"
+	"github.com/bernardoforcillo/globify/internal/config"
 	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
+	"github.com/bernardoforcillo/globify/internal/processor"
+	"github.com/bernardoforcillo/globify/internal/translator"
 )
 
 // TestApp performs integration testing of the app functionality
@@ -101,27 +113,784 @@ func TestApp(t *testing.T) {
 	// 4. Verify the output files
 }
 
-// TestAppWithCustomDependencies tests the app with custom dependencies
-// This is a more advanced test that would allow testing without external services
+// countingTranslator prefixes the target language and records every text it translates
+type countingTranslator struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (c *countingTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.texts = append(c.texts, text)
+	return fmt.Sprintf("[%s] %s", to, text), nil
+}
+
+// TestAppWithCustomDependencies runs the translation process with a mocked translator
+// and checks that only keys whose source changed are translated again
 func TestAppWithCustomDependencies(t *testing.T) {
-	// Skip this test for now - it would require refactoring App to accept dependencies
-	t.Skip("Skipping test that requires refactoring App to accept dependencies")
-
-	// Future enhancement: Add a constructor that allows injecting dependencies:
-	//
-	// func NewAppWithDependencies(
-	//     cfg *config.Config,
-	//     trans translator.Translator,
-	//     fm files.FileManager,
-	//     proc processor.ObjectProcessor,
-	// ) *App {
-	//     return &App{
-	//         config:      cfg,
-	//         translator:  trans,
-	//         fileManager: fm,
-	//         processor:   proc,
-	//     }
-	// }
-	//
-	// This would allow complete mocking of all dependencies for testing
-}
\ No newline at end of file
+	tempDir, err := os.MkdirTemp("", "globify-app-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"en", "fr"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	trans := &countingTranslator{}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+
+	enFilePath := filepath.Join(tempDir, "en.json")
+	frFilePath := filepath.Join(tempDir, "fr.json")
+
+	err = fm.Write(enFilePath, files.LanguageContent{
+		"greeting": "Hello",
+		"farewell": "Goodbye",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	// A human translation that exists before the first run must be kept
+	err = fm.Write(frFilePath, files.LanguageContent{
+		"greeting": "Salut",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write French file: %v", err)
+	}
+
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(trans.texts, []string{"Goodbye"}) {
+		t.Errorf("First run translated %v, want [Goodbye]", trans.texts)
+	}
+
+	// A second run without source changes must not call the translator
+	trans.texts = nil
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(trans.texts) != 0 {
+		t.Errorf("Second run translated %v, want nothing", trans.texts)
+	}
+
+	// Changing a source string only retranslates that key
+	err = fm.Write(enFilePath, files.LanguageContent{
+		"greeting": "Hello there",
+		"farewell": "Goodbye",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	trans.texts = nil
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(trans.texts, []string{"Hello there"}) {
+		t.Errorf("Third run translated %v, want [Hello there]", trans.texts)
+	}
+
+	frContent, err := fm.Read(frFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	want := files.LanguageContent{
+		"greeting": "[fr] Hello there",
+		"farewell": "[fr] Goodbye",
+	}
+	if !reflect.DeepEqual(frContent, want) {
+		t.Errorf("French file = %v, want %v", frContent, want)
+	}
+}
+
+// flakyTranslator keeps brand names as they are and fails the texts in broken
+type flakyTranslator struct {
+	countingTranslator
+	broken map[string]bool
+}
+
+func (f *flakyTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	f.countingTranslator.Translate(ctx, text, from, to)
+	if f.broken[text] {
+		return "", fmt.Errorf("mock translation error")
+	}
+	if text == "Globify" {
+		return text, nil
+	}
+	return fmt.Sprintf("[%s] %s", to, text), nil
+}
+
+// TestAppLocksIdenticalTranslations checks that translations identical to
+// their source are locked, while strings that failed are sent again
+func TestAppLocksIdenticalTranslations(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	err := fm.Write(filepath.Join(tempDir, "en.json"), files.LanguageContent{"brand": "Globify", "greeting": "Hello"})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	trans := &flakyTranslator{broken: map[string]bool{"Hello": true}}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	translationLock, err := lock.Load(cfg.LockFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]lock.Entry{"brand": {Hash: lock.Hash("Globify")}}
+	if !reflect.DeepEqual(translationLock.Languages["fr"], want) {
+		t.Errorf("lock entries = %v, want %v", translationLock.Languages["fr"], want)
+	}
+
+	// Only the failed string is sent again
+	trans.texts = nil
+	trans.broken = nil
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(trans.texts, []string{"Hello"}) {
+		t.Errorf("second Run() translated %v, want [Hello]", trans.texts)
+	}
+}
+
+// TestAppKeepsBaseKeyOrder checks that target files are written in the key
+// order of the base file, even when it changes between runs
+func TestAppKeepsBaseKeyOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"en", "fr"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	trans := &countingTranslator{}
+	globify := app.NewAppWithDependencies(cfg, trans, files.NewJSONManager(), processor.NewSimpleProcessor(trans))
+
+	enFilePath := filepath.Join(tempDir, "en.json")
+	frFilePath := filepath.Join(tempDir, "fr.json")
+	writeBase := func(base string) {
+		if err := os.WriteFile(enFilePath, []byte(base), 0644); err != nil {
+			t.Fatalf("Failed to write English file: %v", err)
+		}
+	}
+
+	writeBase(`{"title": "Title", "body": {"second": "Second", "first": "First"}, "footer": "Footer"}`)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	data, err := os.ReadFile(frFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	want := `{
+  "title": "[fr] Title",
+  "body": {
+    "second": "[fr] Second",
+    "first": "[fr] First"
+  },
+  "footer": "[fr] Footer"
+}
+`
+	if string(data) != want {
+		t.Errorf("First run wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Moving keys in the base file moves them in the target without translating again
+	trans.texts = nil
+	writeBase(`{"footer": "Footer", "title": "Title", "body": {"second": "Second", "first": "First"}}`)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(trans.texts) != 0 {
+		t.Errorf("Second run translated %v, want nothing", trans.texts)
+	}
+	data, err = os.ReadFile(frFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	want = `{
+  "footer": "[fr] Footer",
+  "title": "[fr] Title",
+  "body": {
+    "second": "[fr] Second",
+    "first": "[fr] First"
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("Second run wrote:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+// TestAppDryRun checks that a dry run plans the translation without calling
+// the translator or writing any file
+func TestAppDryRun(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg := &config.Config{
+		TranslationType: "ast-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	err := fm.Write(filepath.Join(tempDir, "en.json"), files.LanguageContent{
+		"greeting": "Hello, {name}!",
+		"farewell": "Goodbye",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+	frFilePath := filepath.Join(tempDir, "fr.json")
+	err = fm.Write(frFilePath, files.LanguageContent{
+		"farewell": "Au revoir",
+		"obsolete": "Obsolète",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write French file: %v", err)
+	}
+
+	trans := &countingTranslator{}
+	proc, err := processor.CreateProcessor(cfg.TranslationType, trans)
+	if err != nil {
+		t.Fatalf("CreateProcessor() error = %v", err)
+	}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, proc)
+
+	plans, err := globify.Plan()
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	want := []app.LanguagePlan{{
+		Language:   "fr",
+		Translate:  []string{"greeting"},
+		Reuse:      []string{"farewell"},
+		Drop:       []string{"obsolete"},
+		Characters: len("Hello, ") + len("!"),
+	}}
+	if !reflect.DeepEqual(plans, want) {
+		t.Errorf("Plan() = %+v, want %+v", plans, want)
+	}
+
+	var output bytes.Buffer
+	globify.SetDryRun(true)
+	globify.SetOutput(&output)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(trans.texts) != 0 {
+		t.Errorf("Dry run translated %v, want nothing", trans.texts)
+	}
+	if !strings.Contains(output.String(), "fr: 1 to translate, 1 reused, 1 dropped, 8 characters") {
+		t.Errorf("Dry run output = %q", output.String())
+	}
+	if _, err := os.Stat(cfg.LockFile); !os.IsNotExist(err) {
+		t.Errorf("Dry run wrote the lock file")
+	}
+	frContent, err := fm.Read(frFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	if _, ok := frContent["obsolete"]; !ok {
+		t.Errorf("Dry run modified the French file")
+	}
+}
+
+// TestAppCheck checks that every kind of issue is reported
+func TestAppCheck(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg := &config.Config{
+		TranslationType: "ast-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	base := files.LanguageContent{
+		"greeting": "Hello, {name}!",
+		"farewell": "Goodbye",
+		"changed":  "New source",
+		"missing":  "Missing",
+		"nested": map[string]interface{}{
+			"key1": "Nested value",
+		},
+		"flat": "Flat value",
+	}
+	if err := fm.Write(filepath.Join(tempDir, "en.json"), base); err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+	err := fm.Write(filepath.Join(tempDir, "fr.json"), files.LanguageContent{
+		"greeting": "Bonjour, {nom} !",
+		"farewell": "Au revoir",
+		"changed":  "Ancienne source",
+		"nested":   "Valeur",
+		"flat": map[string]interface{}{
+			"key1": "Valeur plate",
+		},
+		"extra": "En trop",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write French file: %v", err)
+	}
+
+	translationLock := lock.New()
+	translationLock.Languages["fr"] = map[string]lock.Entry{
+		"farewell": {Hash: lock.Hash("Goodbye")},
+		"changed":  {Hash: lock.Hash("Old source")},
+	}
+	if err := translationLock.Save(cfg.LockFile); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+
+	globify := app.NewAppWithDependencies(cfg, nil, fm, nil)
+	issues, err := globify.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	got := make(map[string]app.IssueKind)
+	for _, issue := range issues {
+		got[issue.Key] = issue.Kind
+	}
+	want := map[string]app.IssueKind{
+		"changed":  app.StaleKey,
+		"extra":    app.ExtraKey,
+		"flat":     app.TypeMismatch,
+		"greeting": app.PlaceholderMismatch,
+		"missing":  app.MissingKey,
+		"nested":   app.TypeMismatch,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() issues = %v, want %v", got, want)
+	}
+}
+
+// TestAppMultiLanguageFile checks that every language of a String Catalog is
+// translated into the same file
+func TestAppMultiLanguageFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "xcstrings",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr", "de"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewXCStringsManager()
+	catalogPath := filepath.Join(tempDir, "Localizable.xcstrings")
+	if err := fm.Write(catalogPath, files.LanguageContent{"Hello": "Hello", "Goodbye": "Goodbye"}); err != nil {
+		t.Fatalf("Failed to write catalog: %v", err)
+	}
+
+	trans := &countingTranslator{}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for _, lang := range cfg.Languages {
+		content, err := fm.ReadLanguage(catalogPath, lang)
+		if err != nil {
+			t.Fatalf("ReadLanguage() error = %v", err)
+		}
+		want := files.LanguageContent{
+			"Hello":   fmt.Sprintf("[%s] Hello", lang),
+			"Goodbye": fmt.Sprintf("[%s] Goodbye", lang),
+		}
+		if !reflect.DeepEqual(content, want) {
+			t.Errorf("%s content = %v, want %v", lang, content, want)
+		}
+	}
+
+	issues, err := globify.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Check() issues = %v, want none", issues)
+	}
+}
+
+// TestAppPluralForms checks that every plural form of a gettext target
+// language is translated, however many forms the language has
+func TestAppPluralForms(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "po",
+		BaseLanguage:    "en",
+		Languages:       []string{"ru", "zh_CN"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	base := "msgid \"\"\nmsgstr \"\"\n\"Language: en\\n\"\n\nmsgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"\"\nmsgstr[1] \"\"\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "en.po"), []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	fm := files.NewPOManager()
+	trans := &countingTranslator{}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]files.LanguageContent{
+		// The text after the placeholder is translated on its own
+		"ru":    {"%d file": map[string]interface{}{"0": "%d[ru]  file", "1": "%d[ru]  files", "2": "%d[ru]  files"}},
+		"zh_CN": {"%d file": map[string]interface{}{"0": "%d[zh_CN]  files"}},
+	}
+	for lang, wantContent := range want {
+		content, err := fm.Read(filepath.Join(tempDir, lang+".po"))
+		if err != nil {
+			t.Fatalf("Failed to read %s file: %v", lang, err)
+		}
+		if !reflect.DeepEqual(content, wantContent) {
+			t.Errorf("%s content = %v, want %v", lang, content, wantContent)
+		}
+	}
+
+	// Every form is locked, so nothing is translated again
+	translated := len(trans.texts)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if len(trans.texts) != translated {
+		t.Errorf("second Run() translated %v", trans.texts[translated:])
+	}
+	issues, err := globify.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Check() issues = %v, want none", issues)
+	}
+}
+
+// TestAppAndroidPluralQuantities checks that Android plurals get every
+// quantity of the target language
+func TestAppAndroidPluralQuantities(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "xml",
+		BaseLanguage:    "en",
+		Languages:       []string{"ru", "ja"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	base := "<resources>\n    <plurals name=\"songs\">\n        <item quantity=\"one\">One song</item>\n        <item quantity=\"other\">Many songs</item>\n    </plurals>\n</resources>\n"
+	if err := os.MkdirAll(filepath.Join(tempDir, "values"), 0755); err != nil {
+		t.Fatalf("Failed to create values folder: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "values", "strings.xml"), []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	fm := files.NewAndroidManager()
+	trans := &countingTranslator{}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]files.LanguageContent{
+		"ru": {"songs": map[string]interface{}{"one": "[ru] One song", "few": "[ru] Many songs", "many": "[ru] Many songs", "other": "[ru] Many songs"}},
+		"ja": {"songs": map[string]interface{}{"other": "[ja] Many songs"}},
+	}
+	for lang, wantContent := range want {
+		content, err := fm.Read(filepath.Join(tempDir, "values-"+lang, "strings.xml"))
+		if err != nil {
+			t.Fatalf("Failed to read %s file: %v", lang, err)
+		}
+		if !reflect.DeepEqual(content, wantContent) {
+			t.Errorf("%s content = %v, want %v", lang, content, wantContent)
+		}
+	}
+
+	// Every quantity is locked, so nothing is translated again
+	translated := len(trans.texts)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if len(trans.texts) != translated {
+		t.Errorf("second Run() translated %v", trans.texts[translated:])
+	}
+	issues, err := globify.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Check() issues = %v, want none", issues)
+	}
+}
+
+// quotaTranslator translates like countingTranslator within a character quota
+type quotaTranslator struct {
+	countingTranslator
+	usage translator.Usage
+}
+
+func (q *quotaTranslator) Usage(ctx context.Context) (*translator.Usage, error) {
+	usage := q.usage
+	return &usage, nil
+}
+
+// TestAppQuota checks that runs exceeding the provider quota stop before writing anything
+func TestAppQuota(t *testing.T) {
+	tempDir := t.TempDir()
+
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr", "es"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	err := fm.Write(filepath.Join(tempDir, "en.json"), files.LanguageContent{
+		"greeting": "Hello",
+		"farewell": "Goodbye",
+	})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	// Both languages need 12 characters, but only 20 remain
+	trans := &quotaTranslator{usage: translator.Usage{Provider: "Fake", Characters: 480, Limit: 500}}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+
+	checks, err := globify.CheckQuota(context.Background())
+	if err != nil {
+		t.Fatalf("CheckQuota() error = %v", err)
+	}
+	want := []app.QuotaCheck{{Provider: "Fake", Languages: []string{"fr", "es"}, Characters: 24, Remaining: 20}}
+	if !reflect.DeepEqual(checks, want) {
+		t.Errorf("CheckQuota() = %+v, want %+v", checks, want)
+	}
+	if !checks[0].Exceeded() {
+		t.Errorf("Exceeded() = false, want true")
+	}
+
+	if err := globify.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "only 20 remain in the Fake quota") {
+		t.Fatalf("Run() error = %v, want a quota error", err)
+	}
+	if len(trans.texts) != 0 {
+		t.Errorf("Run() translated %v before checking the quota", trans.texts)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "fr.json")); !os.IsNotExist(err) {
+		t.Errorf("Run() wrote the French file despite the quota")
+	}
+
+	globify.SetIgnoreQuota(true)
+	if err := globify.Run(context.Background()); err != nil {
+		t.Fatalf("Run() with ignored quota error = %v", err)
+	}
+	if len(trans.texts) != 4 {
+		t.Errorf("Run() with ignored quota translated %v", trans.texts)
+	}
+
+	// Accounts without a limit are never exceeded
+	trans.usage = translator.Usage{Provider: "Fake", Characters: 1000}
+	if check := (app.QuotaCheck{Characters: 1000, Remaining: trans.usage.Remaining()}); check.Exceeded() {
+		t.Errorf("Exceeded() = true for an account without limit")
+	}
+}
+
+// cancellingTranslator translates like countingTranslator and cancels the
+// run once it translated a given number of texts
+type cancellingTranslator struct {
+	countingTranslator
+	cancel context.CancelFunc
+	after  int
+}
+
+func (c *cancellingTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	translated, err := c.countingTranslator.Translate(ctx, text, from, to)
+	c.mu.Lock()
+	if len(c.texts) >= c.after {
+		c.cancel()
+	}
+	c.mu.Unlock()
+	return translated, err
+}
+
+// TestAppInterrupted checks that an interrupted run writes and locks the
+// translations it finished, and leaves the other keys for the next run
+func TestAppInterrupted(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr", "es"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	enFilePath := filepath.Join(tempDir, "en.json")
+	frFilePath := filepath.Join(tempDir, "fr.json")
+	write := func(content files.LanguageContent) {
+		if err := fm.Write(enFilePath, content); err != nil {
+			t.Fatalf("Failed to write English file: %v", err)
+		}
+	}
+
+	write(files.LanguageContent{"greeting": "Hello", "farewell": "Goodbye", "thanks": "Thanks"})
+	trans := &countingTranslator{}
+	if err := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans)).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Change every source and interrupt the run after the first translation
+	write(files.LanguageContent{"greeting": "Hi", "farewell": "Bye", "thanks": "Thank you"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupted := &cancellingTranslator{cancel: cancel, after: 1}
+	globify := app.NewAppWithDependencies(cfg, interrupted, fm, processor.NewSimpleProcessor(interrupted))
+	if err := globify.Run(ctx); err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("Run() error = %v, want an interruption", err)
+	}
+	if len(interrupted.texts) != 1 {
+		t.Fatalf("Interrupted run translated %v, want a single text", interrupted.texts)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "es.json")); err != nil {
+		t.Errorf("Spanish file of the first run is missing: %v", err)
+	}
+
+	// The finished key has its new translation, the others keep the previous one
+	sources := map[string][2]string{"greeting": {"Hello", "Hi"}, "farewell": {"Goodbye", "Bye"}, "thanks": {"Thanks", "Thank you"}}
+	want := files.LanguageContent{}
+	var pending []string
+	for key, source := range sources {
+		if source[1] == interrupted.texts[0] {
+			want[key] = "[fr] " + source[1]
+		} else {
+			want[key] = "[fr] " + source[0]
+			pending = append(pending, source[1])
+		}
+	}
+	frContent, err := fm.Read(frFilePath)
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	if !reflect.DeepEqual(frContent, want) {
+		t.Errorf("French file = %v, want %v", frContent, want)
+	}
+
+	// The next run translates the rest of French and every Spanish key
+	trans = &countingTranslator{}
+	if err := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans)).Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	wantTexts := append(pending, "Bye", "Hi", "Thank you")
+	sort.Strings(wantTexts)
+	sort.Strings(trans.texts)
+	if !reflect.DeepEqual(trans.texts, wantTexts) {
+		t.Errorf("Next run translated %v, want %v", trans.texts, wantTexts)
+	}
+}
+
+// exhaustedTranslator translates like countingTranslator until its quota
+// of texts is used up
+type exhaustedTranslator struct {
+	countingTranslator
+	quota int
+}
+
+func (e *exhaustedTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	e.mu.Lock()
+	exhausted := len(e.texts) >= e.quota
+	e.mu.Unlock()
+	if exhausted {
+		return "", &translator.APIError{Provider: "Fake", StatusCode: 456, Message: "Quota exceeded", Kind: translator.ErrQuota}
+	}
+	return e.countingTranslator.Translate(ctx, text, from, to)
+}
+
+// TestAppStopsOnFatalErrors checks that a used up quota stops the run,
+// keeping the translations finished before it
+func TestAppStopsOnFatalErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "en",
+		Languages:       []string{"fr", "es"},
+		Folder:          tempDir,
+		LockFile:        filepath.Join(tempDir, "globify.lock"),
+	}
+
+	fm := files.NewJSONManager()
+	err := fm.Write(filepath.Join(tempDir, "en.json"), files.LanguageContent{"greeting": "Hello", "farewell": "Goodbye"})
+	if err != nil {
+		t.Fatalf("Failed to write English file: %v", err)
+	}
+
+	trans := &exhaustedTranslator{quota: 1}
+	globify := app.NewAppWithDependencies(cfg, trans, fm, processor.NewSimpleProcessor(trans))
+	if err := globify.Run(context.Background()); !errors.Is(err, translator.ErrQuota) {
+		t.Fatalf("Run() error = %v, want %v", err, translator.ErrQuota)
+	}
+
+	frContent, err := fm.Read(filepath.Join(tempDir, "fr.json"))
+	if err != nil {
+		t.Fatalf("Failed to read French file: %v", err)
+	}
+	translated := 0
+	for _, value := range frContent {
+		if strings.HasPrefix(value.(string), "[fr] ") {
+			translated++
+		}
+	}
+	if len(frContent) != 2 || translated != 1 {
+		t.Errorf("French file = %v, want one translated and one source string", frContent)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "es.json")); !os.IsNotExist(err) {
+		t.Errorf("Run() went on with Spanish after the quota was used up")
+	}
+}
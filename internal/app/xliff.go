@@ -0,0 +1,264 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
+	"github.com/bernardoforcillo/globify/internal/xliff"
+)
+
+// staleNote is attached to exported strings whose previous translation is outdated
+const staleNote = "The base language string changed since this translation was made"
+
+// ExportResult describes the XLIFF file of a target language
+type ExportResult struct {
+	Language string `json:"language"`
+	File     string `json:"file"`
+	// Keys lists the exported keys
+	Keys []string `json:"keys"`
+}
+
+// ImportResult describes the translations merged from an XLIFF file
+type ImportResult struct {
+	Language string `json:"language"`
+	File     string `json:"file"`
+	// Imported lists the keys whose translation was merged and approved
+	Imported []string `json:"imported"`
+	// Skipped lists the keys without a finished translation, missing from
+	// the base language or whose base string changed since the export
+	Skipped []string `json:"skipped"`
+}
+
+// Export writes an XLIFF file of the given version into folder for every
+// target language, holding the strings that are not translated yet and
+// those whose base string changed since they were translated, with their
+// outdated translation. Languages with nothing to translate get no file
+// and nothing is written in dry-run mode.
+func (a *App) Export(folder, version string) ([]ExportResult, error) {
+	baseContent, err := a.readBase()
+	if err != nil {
+		return nil, err
+	}
+
+	translationLock, err := lock.Load(a.lockFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ExportResult
+	for _, lang := range a.targetLanguages() {
+		previousContent, _, err := a.readTarget(lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target file %s: %w", a.filePath(lang), err)
+		}
+
+		doc := &xliff.Document{
+			Version:        version,
+			SourceLanguage: a.config.BaseLanguage,
+			TargetLanguage: lang,
+			Original:       filepath.Base(a.filePath(lang)),
+		}
+		pendingContent := translationLock.Pending(lang, a.languageBase(lang, baseContent), previousContent)
+		doc.Units = exportUnits(pendingContent, previousContent, "")
+		if len(doc.Units) == 0 {
+			continue
+		}
+		sort.Slice(doc.Units, func(i, j int) bool {
+			return doc.Units[i].Key < doc.Units[j].Key
+		})
+
+		result := ExportResult{Language: lang, File: filepath.Join(folder, lang+".xlf")}
+		for _, unit := range doc.Units {
+			result.Keys = append(result.Keys, unit.Key)
+		}
+
+		if !a.dryRun {
+			buffer := &bytes.Buffer{}
+			if err := xliff.Write(buffer, doc); err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(folder, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create directory %s: %w", folder, err)
+			}
+			if err := os.WriteFile(result.File, buffer.Bytes(), 0644); err != nil {
+				return nil, fmt.Errorf("failed to write file %s: %w", result.File, err)
+			}
+			a.logf("Exported %d keys to %s", len(result.Keys), result.File)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// exportUnits converts the pending strings of a target language into XLIFF units
+func exportUnits(pending, previous files.LanguageContent, prefix string) []xliff.Unit {
+	var units []xliff.Unit
+
+	for key, value := range pending {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+		if nested, ok := files.AsContent(value); ok {
+			// Pending arrays are objects keyed by index
+			previousNested, ok := files.AsContent(previous[key])
+			if !ok {
+				previousNested, _ = files.ArrayContent(previous[key])
+			}
+			units = append(units, exportUnits(nested, previousNested, path)...)
+			continue
+		}
+
+		source, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		unit := xliff.Unit{Key: path, Source: source, State: xliff.StateInitial}
+		metadata, _ := files.AsContent(pending["@"+key])
+		if description, ok := metadata["description"].(string); ok && description != "" {
+			unit.Notes = append(unit.Notes, description)
+		}
+		// Pending strings with a translation of their own are stale
+		if translation, ok := previous[key].(string); ok && translation != source {
+			unit.Target = translation
+			unit.Notes = append(unit.Notes, staleNote)
+		}
+		units = append(units, unit)
+	}
+
+	return units
+}
+
+// Import merges the finished translations of an XLIFF file into the file of
+// its target language and records them as approved in the lock file. Units
+// whose source no longer matches the base language are skipped so outdated
+// reviews never overwrite newer strings. Nothing is written in dry-run mode.
+func (a *App) Import(filePath string) (*ImportResult, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	doc, err := xliff.Read(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	lang := doc.TargetLanguage
+	if !contains(a.targetLanguages(), lang) {
+		return nil, fmt.Errorf("target language '%s' of %s is not a configured target language", lang, filePath)
+	}
+	if doc.SourceLanguage != a.config.BaseLanguage {
+		return nil, fmt.Errorf("source language '%s' of %s is not the base language '%s'", doc.SourceLanguage, filePath, a.config.BaseLanguage)
+	}
+
+	baseDoc, err := a.readBaseDocument()
+	if err != nil {
+		return nil, err
+	}
+	baseContent := a.languageBase(lang, baseDoc.Content)
+	baseStrings := files.Flatten(baseContent)
+
+	result := &ImportResult{Language: lang, File: filePath}
+	translations := make(map[string]string)
+	for _, unit := range doc.Units {
+		if source, ok := baseStrings[unit.Key].(string); !ok || source != unit.Source || !unit.Done() {
+			result.Skipped = append(result.Skipped, unit.Key)
+			continue
+		}
+		translations[unit.Key] = unit.Target
+		result.Imported = append(result.Imported, unit.Key)
+	}
+	sort.Strings(result.Imported)
+	sort.Strings(result.Skipped)
+
+	if a.dryRun || len(translations) == 0 {
+		return result, nil
+	}
+
+	targetContent, _, err := a.readTarget(lang)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target file %s: %w", a.filePath(lang), err)
+	}
+
+	targetFilePath := a.filePath(lang)
+	a.logf("Writing %d imported translations to %s", len(translations), targetFilePath)
+	if err := a.writeLanguage(lang, applyTranslations(baseContent, targetContent, "", translations), baseDoc.Order); err != nil {
+		return nil, fmt.Errorf("failed to write translated file %s: %w", targetFilePath, err)
+	}
+
+	translationLock, err := lock.Load(a.lockFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range result.Imported {
+		translationLock.Approve(lang, key, baseStrings[key].(string))
+	}
+	if err := translationLock.Save(a.lockFile); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyTranslations returns a copy of target with the translations, keyed by
+// dotted path, set at the place of their key in base
+func applyTranslations(base, target files.LanguageContent, prefix string, translations map[string]string) files.LanguageContent {
+	result := make(files.LanguageContent, len(target))
+	for key, value := range target {
+		result[key] = value
+	}
+
+	for key, value := range base {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+		if items, ok := files.ArrayContent(value); ok {
+			targetItems, _ := files.ArrayContent(target[key])
+			applied := applyTranslations(items, targetItems, path, translations)
+			// Items without a translation yet keep their source
+			for index, item := range items {
+				if _, ok := applied[index]; !ok {
+					applied[index] = item
+				}
+			}
+			result[key] = files.ContentArray(applied, len(items))
+			continue
+		}
+		if nested, ok := files.AsContent(value); ok {
+			targetNested, _ := files.AsContent(target[key])
+			if applied := applyTranslations(nested, targetNested, path, translations); len(applied) > 0 {
+				result[key] = applied
+			}
+			continue
+		}
+
+		if translation, ok := translations[path]; ok {
+			result[key] = translation
+		}
+	}
+
+	return result
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
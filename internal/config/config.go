@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 )
 
 // Config represents the application configuration
@@ -15,10 +16,27 @@ type Config struct {
 	BaseLanguage    string   `json:"baseLanguage"`
 	Languages       []string `json:"languages"`
 	Folder          string   `json:"folder"`
+	// LockFile is the path of the translation lock file, defaults to globify.lock
+	LockFile string `json:"lockFile,omitempty"`
+	// Provider selects the translation provider, defaults to DeepL
+	Provider *Provider `json:"provider,omitempty"`
 }
 
-// Language code regex pattern
-var langRegex = regexp.MustCompile(`^[a-z]{2}(-[A-Z][a-z]{3})?$`)
+// Provider selects a translation provider and its options
+type Provider struct {
+	// Name is the name the provider is registered under, like "deepl"
+	Name    string                 `json:"name,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+	// Languages overrides the provider of some target languages. Overrides
+	// without a name keep the provider and only change some of its options.
+	Languages map[string]*Provider `json:"languages,omitempty"`
+}
+
+// FileExtensions lists the supported translation file extensions
+var FileExtensions = []string{"json", "yaml", "yml", "po", "xml", "strings", "stringsdict", "xcstrings", "arb"}
+
+// Language code regex pattern, with an optional script and region like 'zh-Hans' or 'pt-BR'
+var langRegex = regexp.MustCompile(`^[a-z]{2}(-[A-Z][a-z]{3})?(-[A-Z]{2})?$`)
 
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
@@ -28,8 +46,8 @@ func (c *Config) Validate() error {
 	}
 
 	// Check file extension
-	if c.FileExtension != "json" {
-		return fmt.Errorf("fileExtension must be 'json'")
+	if !contains(FileExtensions, c.FileExtension) {
+		return fmt.Errorf("fileExtension must be one of '%s'", strings.Join(FileExtensions, "', '"))
 	}
 
 	// Check base language
@@ -49,12 +67,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("folder cannot be empty")
 	}
 
+	// Check provider overrides
+	if c.Provider != nil {
+		for lang, override := range c.Provider.Languages {
+			if !contains(c.Languages, lang) || lang == c.BaseLanguage {
+				return fmt.Errorf("provider override for '%s' must be one of the target languages", lang)
+			}
+			if override == nil {
+				return fmt.Errorf("provider override for '%s' cannot be empty", lang)
+			}
+			if len(override.Languages) > 0 {
+				return fmt.Errorf("provider override for '%s' cannot have language overrides", lang)
+			}
+		}
+	}
+
 	return nil
 }
 
-// LoadConfig loads the configuration from a file
+// DefaultFileName is the configuration file looked up in the working directory
+const DefaultFileName = "globify.config.json"
+
+// LoadConfig loads the configuration from the working directory
 func LoadConfig() (*Config, error) {
-	configFiles := []string{"globify.config.json"}
+	configFiles := []string{DefaultFileName}
 	
 	// Get current working directory
 	cwd, err := os.Getwd()
@@ -76,6 +112,11 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("no config file found (tried: %v)", configFiles)
 	}
 
+	return LoadConfigFile(configFile)
+}
+
+// LoadConfigFile loads the configuration from the given file
+func LoadConfigFile(configFile string) (*Config, error) {
 	// Read the config file
 	data, err := os.ReadFile(configFile)
 	if err != nil {
@@ -94,4 +135,48 @@ func LoadConfig() (*Config, error) {
 	}
 
 	return &config, nil
-}
\ No newline at end of file
+}
+
+// Save validates the configuration and writes it to a file
+func (c *Config) Save(configFile string) error {
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+
+	if err := os.WriteFile(configFile, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", configFile, err)
+	}
+
+	return nil
+}
+
+// TargetLanguages returns the configured languages without the base language
+func (c *Config) TargetLanguages() []string {
+	var languages []string
+	for _, lang := range c.Languages {
+		if lang != c.BaseLanguage {
+			languages = append(languages, lang)
+		}
+	}
+	return languages
+}
+
+// IsLanguageCode reports whether code is a valid language code like 'en', 'zh-Hans' or 'pt-BR'
+func IsLanguageCode(code string) bool {
+	return langRegex.MatchString(code)
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/icu"
+)
+
+// Detection is the result of scanning a project for translation files
+type Detection struct {
+	// Config holds the guessed configuration
+	Config Config
+	// Files lists the translation files the guess is based on
+	Files []string
+}
+
+// skippedDirs are never scanned for translation files
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// Detect scans root for translation files named after a language code and
+// guesses a configuration from the folder containing most of them. The base
+// language is 'en' when present, otherwise the file with the most keys. The
+// translation type is 'ast-json' when the base file uses ICU syntax.
+func Detect(root string) (*Detection, error) {
+	groups := make(map[string][]string)
+
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.TrimPrefix(filepath.Ext(path), ".")
+		lang := fileLanguage(path, ext)
+		if contains(FileExtensions, ext) && IsLanguageCode(lang) {
+			group := filepath.Join(filepath.Dir(path), "*."+ext)
+			groups[group] = append(groups[group], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no translation files found in %s", root)
+	}
+
+	// Pick the folder with the most translation files, preferring shallow paths
+	var best string
+	for group, paths := range groups {
+		if best == "" ||
+			len(paths) > len(groups[best]) ||
+			len(paths) == len(groups[best]) && len(group) < len(best) ||
+			len(paths) == len(groups[best]) && len(group) == len(best) && group < best {
+			best = group
+		}
+	}
+	paths := groups[best]
+	sort.Strings(paths)
+
+	ext := strings.TrimPrefix(filepath.Ext(best), ".")
+	fm, err := files.NewFileManager(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := filepath.Rel(root, filepath.Dir(best))
+	if err != nil {
+		folder = filepath.Dir(best)
+	}
+
+	detection := &Detection{
+		Config: Config{
+			TranslationType: "simple-json",
+			FileExtension:   ext,
+			Folder:          folder,
+		},
+		Files: paths,
+	}
+
+	// Choose the base language
+	var baseContent files.LanguageContent
+	mostKeys := -1
+	for _, path := range paths {
+		lang := fileLanguage(path, ext)
+		content, err := fm.Read(path)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := len(files.Flatten(content))
+		if lang == "en" || detection.Config.BaseLanguage != "en" && keys > mostKeys {
+			detection.Config.BaseLanguage = lang
+			baseContent = content
+			mostKeys = keys
+		}
+	}
+
+	for _, path := range paths {
+		lang := fileLanguage(path, ext)
+		if lang != detection.Config.BaseLanguage {
+			detection.Config.Languages = append(detection.Config.Languages, lang)
+		}
+	}
+
+	if usesICU(baseContent) {
+		detection.Config.TranslationType = "ast-json"
+	}
+
+	return detection, nil
+}
+
+// fileLanguage returns the language code a translation file is named after,
+// like 'pt-BR' for both pt-BR.json and app_pt_BR.arb
+func fileLanguage(path, ext string) string {
+	lang := strings.TrimSuffix(filepath.Base(path), "."+ext)
+	if ext == "arb" {
+		lang = strings.ReplaceAll(strings.TrimPrefix(lang, "app_"), "_", "-")
+	}
+	return lang
+}
+
+// usesICU reports whether any string of content contains ICU message syntax
+func usesICU(content files.LanguageContent) bool {
+	for _, value := range files.Flatten(content) {
+		text, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		elements, err := icu.Parse(text)
+		if err == nil && len(icu.Placeholders(elements)) > 0 {
+			return true
+		}
+	}
+	return false
+}
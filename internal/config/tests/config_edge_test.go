@@ -90,6 +90,28 @@ func TestConfigValidateEdgeCases(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Valid region language codes",
+			config: config.Config{
+				TranslationType: "simple-json",
+				FileExtension:   "xml",
+				BaseLanguage:    "en",
+				Languages:       []string{"pt-BR", "zh-Hant-TW"},
+				Folder:          "app/src/main/res",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid region language codes",
+			config: config.Config{
+				TranslationType: "simple-json",
+				FileExtension:   "json",
+				BaseLanguage:    "en",
+				Languages:       []string{"pt-br"}, // Region must be uppercase
+				Folder:          "translations",
+			},
+			wantErr: true,
+		},
 		{
 			name: "Relative folder path",
 			config: config.Config{
@@ -39,7 +39,7 @@ func TestConfigValidate(t *testing.T) {
 			name: "Invalid file extension",
 			config: config.Config{
 				TranslationType: "simple-json",
-				FileExtension:   "yaml",
+				FileExtension:   "txt",
 				BaseLanguage:    "en",
 				Languages:       []string{"es", "fr", "de"},
 				Folder:          "translations",
@@ -79,6 +79,49 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Provider override for a target language",
+			config: config.Config{
+				TranslationType: "simple-json",
+				FileExtension:   "json",
+				BaseLanguage:    "en",
+				Languages:       []string{"en", "fr", "ja"},
+				Folder:          "translations",
+				Provider: &config.Provider{
+					Name:      "deepl",
+					Languages: map[string]*config.Provider{"ja": {Name: "google"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Provider override for an unknown language",
+			config: config.Config{
+				TranslationType: "simple-json",
+				FileExtension:   "json",
+				BaseLanguage:    "en",
+				Languages:       []string{"en", "fr"},
+				Folder:          "translations",
+				Provider: &config.Provider{
+					Languages: map[string]*config.Provider{"ja": {Name: "google"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Provider override for the base language",
+			config: config.Config{
+				TranslationType: "simple-json",
+				FileExtension:   "json",
+				BaseLanguage:    "en",
+				Languages:       []string{"en", "fr"},
+				Folder:          "translations",
+				Provider: &config.Provider{
+					Languages: map[string]*config.Provider{"en": {Name: "google"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+func TestDetect(t *testing.T) {
+	tempDir := t.TempDir()
+	fm := files.NewJSONManager()
+
+	write := func(path string, content files.LanguageContent) {
+		if err := fm.Write(filepath.Join(tempDir, path), content); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// Without an English file the file with the most keys is the base
+	write("i18n/de.json", files.LanguageContent{"a": "Eins", "b": "Zwei"})
+	write("i18n/fr.json", files.LanguageContent{"a": "Un"})
+	write("i18n/es.json", files.LanguageContent{"a": "Uno"})
+	// A smaller folder and files that are not named after a language are ignored
+	write("other/it.json", files.LanguageContent{"a": "Uno"})
+	write("i18n/package.json", files.LanguageContent{"name": "app"})
+	// Hidden folders are skipped
+	write(".cache/pt.json", files.LanguageContent{"a": "Um"})
+	write(".cache/ru.json", files.LanguageContent{"a": "Один"})
+	write(".cache/ja.json", files.LanguageContent{"a": "一"})
+	write(".cache/ko.json", files.LanguageContent{"a": "일"})
+
+	detection, err := config.Detect(tempDir)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	want := config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "json",
+		BaseLanguage:    "de",
+		Languages:       []string{"es", "fr"},
+		Folder:          "i18n",
+	}
+	if !reflect.DeepEqual(detection.Config, want) {
+		t.Errorf("Detect() config = %+v, want %+v", detection.Config, want)
+	}
+	if len(detection.Files) != 3 {
+		t.Errorf("Detect() found %d files, want 3", len(detection.Files))
+	}
+
+	if _, err := config.Detect(t.TempDir()); err == nil {
+		t.Errorf("Detect() on an empty folder should return error")
+	}
+}
+
+func TestDetectARB(t *testing.T) {
+	tempDir := t.TempDir()
+	fm := files.NewARBManager()
+
+	for lang, greeting := range map[string]string{"en": "Hello", "pt-BR": "Olá"} {
+		path := fm.FilePath(filepath.Join(tempDir, "lib", "l10n"), lang, lang == "en")
+		if err := fm.Write(path, files.LanguageContent{"greeting": greeting}); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	detection, err := config.Detect(tempDir)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	want := config.Config{
+		TranslationType: "simple-json",
+		FileExtension:   "arb",
+		BaseLanguage:    "en",
+		Languages:       []string{"pt-BR"},
+		Folder:          filepath.Join("lib", "l10n"),
+	}
+	if !reflect.DeepEqual(detection.Config, want) {
+		t.Errorf("Detect() config = %+v, want %+v", detection.Config, want)
+	}
+}
@@ -0,0 +1,567 @@
+package files
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AndroidManager implements FileManager for Android string resources.
+//
+// Files live in res/values-<qualifier>/strings.xml, the base language in
+// res/values/strings.xml. Every <string> is a key, <plurals> are nested
+// objects keyed by quantity and <string-array> are nested objects keyed by
+// item index. Plurals of every language get the quantities of its CLDR
+// plural categories. Resources marked translatable="false" are not read and
+// are kept as they are when writing. Writing to an existing file keeps its
+// comments, attributes and resource order.
+type AndroidManager struct {
+	template *androidDocument
+}
+
+// NewAndroidManager creates a new AndroidManager
+func NewAndroidManager() *AndroidManager {
+	return &AndroidManager{}
+}
+
+// FilePath returns the strings.xml file of the values folder of lang
+func (m *AndroidManager) FilePath(folder, lang string, base bool) string {
+	dir := "values"
+	if !base {
+		dir += "-" + AndroidQualifier(lang)
+	}
+	return filepath.Join(folder, dir, "strings.xml")
+}
+
+// Write saves the content to a strings.xml file
+func (m *AndroidManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a strings.xml file with its resources in the order of the document
+func (m *AndroidManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	document, err := readAndroidDocument(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		document = &androidDocument{
+			header:  "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>",
+			trailer: "\n</resources>\n",
+		}
+	}
+
+	document.update(doc.Content, m.template, doc.Order)
+
+	// Write to file
+	if err := os.WriteFile(filePath, document.bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from a strings.xml file
+func (m *AndroidManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a strings.xml file along with the order of its resources
+func (m *AndroidManager) ReadDocument(filePath string) (*Document, error) {
+	document, err := readAndroidDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.template == nil {
+		m.template = document
+	}
+
+	order := &KeyOrder{}
+	for _, entry := range document.entries {
+		if entry.translatable {
+			order.Add(entry.name, nil)
+		}
+	}
+
+	return &Document{Content: document.content(), Order: order}, nil
+}
+
+// ShapeContent implements ContentShaper. Plurals get one item per CLDR
+// plural category of lang, like few and many for Russian, and quantities
+// lang does not use are left out. Strings and string arrays are kept.
+func (m *AndroidManager) ShapeContent(filePath, lang string, base LanguageContent) LanguageContent {
+	shaped := make(LanguageContent, len(base))
+	for key, value := range base {
+		if items, ok := AsContent(value); ok && isPluralContent(items) {
+			shaped[key] = shapePlural(items, lang)
+		} else {
+			shaped[key] = value
+		}
+	}
+	return shaped
+}
+
+// Exists checks if a file exists
+func (m *AndroidManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// AndroidQualifier returns the resource qualifier of a language code, like
+// 'pt-rBR' for 'pt-BR' and 'b+zh+Hans' for 'zh-Hans'
+func AndroidQualifier(lang string) string {
+	parts := strings.Split(lang, "-")
+	switch {
+	case len(parts) == 1:
+		return lang
+	case len(parts) == 2 && len(parts[1]) == 2:
+		return parts[0] + "-r" + parts[1]
+	default:
+		return "b+" + strings.Join(parts, "+")
+	}
+}
+
+// androidQuantities lists the plural quantities in the order Android tools write them
+var androidQuantities = []string{"zero", "one", "two", "few", "many", "other"}
+
+// androidDocument is a parsed strings.xml file
+type androidDocument struct {
+	// header holds everything up to the <resources> start tag
+	header  string
+	entries []*androidEntry
+	// trailer holds everything from the last resource on
+	trailer string
+}
+
+// androidEntry is a single resource of a strings.xml file
+type androidEntry struct {
+	// leading holds the whitespace and comments written before the resource
+	leading string
+	// raw holds the resource as written in the file
+	raw          string
+	kind         string
+	name         string
+	translatable bool
+	// value is a string for <string> and a map for <plurals> and <string-array>
+	value interface{}
+}
+
+// androidElement decodes a resource element
+type androidElement struct {
+	XMLName      xml.Name
+	Name         string `xml:"name,attr"`
+	Translatable string `xml:"translatable,attr"`
+	Inner        string `xml:",innerxml"`
+	Items        []struct {
+		Quantity string `xml:"quantity,attr"`
+		Inner    string `xml:",innerxml"`
+	} `xml:"item"`
+}
+
+// readAndroidDocument parses a strings.xml file
+func readAndroidDocument(filePath string) (*androidDocument, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	document, err := parseAndroidDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Android resources from %s: %w", filePath, err)
+	}
+	return document, nil
+}
+
+// parseAndroidDocument parses the content of a strings.xml file, keeping
+// the raw text of every resource so unchanged ones are written back as they were
+func parseAndroidDocument(data []byte) (*androidDocument, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	document := &androidDocument{}
+
+	// Find the <resources> root
+	for document.header == "" {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("missing <resources> element: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			if start.Name.Local != "resources" {
+				return nil, fmt.Errorf("unexpected root element <%s>", start.Name.Local)
+			}
+			document.header = string(data[:decoder.InputOffset()])
+		}
+	}
+
+	leadingStart := decoder.InputOffset()
+	for {
+		tokenStart := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var element androidElement
+			if err := decoder.DecodeElement(&element, &t); err != nil {
+				return nil, err
+			}
+			entry := &androidEntry{
+				leading: string(data[leadingStart:tokenStart]),
+				raw:     string(data[tokenStart:decoder.InputOffset()]),
+				kind:    element.XMLName.Local,
+				name:    element.Name,
+			}
+			entry.translatable = element.Translatable != "false" && element.Name != "" &&
+				(entry.kind == "string" || entry.kind == "plurals" || entry.kind == "string-array")
+
+			switch entry.kind {
+			case "string":
+				entry.value = decodeAndroidString(element.Inner)
+			case "plurals", "string-array":
+				items := make(map[string]interface{})
+				for i, item := range element.Items {
+					key := item.Quantity
+					if entry.kind == "string-array" {
+						key = strconv.Itoa(i)
+					}
+					items[key] = decodeAndroidString(item.Inner)
+				}
+				entry.value = items
+			}
+
+			document.entries = append(document.entries, entry)
+			leadingStart = decoder.InputOffset()
+
+		case xml.EndElement:
+			document.trailer = string(data[leadingStart:])
+			return document, nil
+		}
+	}
+}
+
+// content converts the translatable resources into LanguageContent
+func (d *androidDocument) content() LanguageContent {
+	content := make(LanguageContent)
+	for _, entry := range d.entries {
+		if !entry.translatable {
+			continue
+		}
+		if items, ok := entry.value.(map[string]interface{}); ok {
+			copied := make(map[string]interface{}, len(items))
+			for key, value := range items {
+				copied[key] = value
+			}
+			content[entry.name] = copied
+		} else {
+			content[entry.name] = entry.value
+		}
+	}
+	return content
+}
+
+// find returns the translatable resource called name
+func (d *androidDocument) find(name string) *androidEntry {
+	if d == nil {
+		return nil
+	}
+	for _, entry := range d.entries {
+		if entry.translatable && entry.name == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// update makes the document hold content. Existing resources keep their
+// comments and attributes, removed resources are dropped and new ones are
+// appended in the order of the template, followed by the others in sorted
+// order. With an order, resources are then moved to their place in it;
+// without one, existing resources keep their position. Untranslatable
+// resources are left untouched.
+func (d *androidDocument) update(content LanguageContent, template *androidDocument, order *KeyOrder) {
+	seen := make(map[string]bool)
+	entries := make([]*androidEntry, 0, len(d.entries))
+
+	for _, entry := range d.entries {
+		if !entry.translatable {
+			entries = append(entries, entry)
+			continue
+		}
+
+		value, ok := content[entry.name]
+		if !ok || seen[entry.name] {
+			continue
+		}
+		seen[entry.name] = true
+
+		entry.set(value)
+		entries = append(entries, entry)
+	}
+
+	var newKeys []string
+	if template != nil {
+		for _, entry := range template.entries {
+			if _, ok := content[entry.name]; ok && entry.translatable && !seen[entry.name] {
+				newKeys = append(newKeys, entry.name)
+				seen[entry.name] = true
+			}
+		}
+	}
+	var otherKeys []string
+	for key := range content {
+		if !seen[key] {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	newKeys = append(newKeys, otherKeys...)
+
+	for _, key := range newKeys {
+		entry := &androidEntry{leading: "\n    ", name: key, translatable: true}
+		if templateEntry := template.find(key); templateEntry != nil {
+			entry.kind = templateEntry.kind
+		}
+		entry.set(content[key])
+		entries = append(entries, entry)
+	}
+
+	reorder(entries, order, func(entry *androidEntry) (string, bool) {
+		return entry.name, entry.translatable
+	})
+
+	d.entries = entries
+}
+
+// set stores a new value in the resource, rendering it again when it changed
+func (e *androidEntry) set(value interface{}) {
+	if nested, ok := AsContent(value); ok {
+		items := make(map[string]interface{}, len(nested))
+		for key, item := range nested {
+			// Descriptions of plural quantities are not part of the resource
+			if !IsMetadataKey(key) {
+				items[key] = fmt.Sprint(item)
+			}
+		}
+		value = items
+		if e.kind != "plurals" && e.kind != "string-array" {
+			e.kind = "plurals"
+			if isArrayContent(items) {
+				e.kind = "string-array"
+			}
+		}
+	} else {
+		value = fmt.Sprint(value)
+		e.kind = "string"
+	}
+
+	if e.raw != "" && fmt.Sprint(e.value) == fmt.Sprint(value) {
+		return
+	}
+	e.value = value
+
+	startTag := fmt.Sprintf("<%s name=\"%s\">", e.kind, html.EscapeString(e.name))
+	if e.raw != "" {
+		if end := strings.Index(e.raw, ">"); end >= 0 {
+			startTag = e.raw[:end+1]
+			if strings.HasSuffix(startTag, "/>") {
+				startTag = strings.TrimRight(strings.TrimSuffix(startTag, "/>"), " ") + ">"
+			}
+		}
+	}
+
+	items, isItems := value.(map[string]interface{})
+	if !isItems {
+		e.raw = startTag + encodeAndroidString(value.(string)) + "</" + e.kind + ">"
+		return
+	}
+
+	indent := "    "
+	if i := strings.LastIndex(e.leading, "\n"); i >= 0 && strings.TrimSpace(e.leading[i+1:]) == "" {
+		indent = e.leading[i+1:]
+	}
+
+	var builder strings.Builder
+	builder.WriteString(startTag)
+	for _, key := range itemKeys(e.kind, items) {
+		builder.WriteString("\n" + indent + "    ")
+		if e.kind == "plurals" {
+			fmt.Fprintf(&builder, "<item quantity=\"%s\">", key)
+		} else {
+			builder.WriteString("<item>")
+		}
+		builder.WriteString(encodeAndroidString(items[key].(string)) + "</item>")
+	}
+	builder.WriteString("\n" + indent + "</" + e.kind + ">")
+	e.raw = builder.String()
+}
+
+// bytes renders the document as a strings.xml file
+func (d *androidDocument) bytes() []byte {
+	var builder strings.Builder
+	builder.WriteString(d.header)
+	for _, entry := range d.entries {
+		builder.WriteString(entry.leading)
+		builder.WriteString(entry.raw)
+	}
+	builder.WriteString(d.trailer)
+	return []byte(builder.String())
+}
+
+// itemKeys returns the keys of plural quantities or array items in the order they are written
+func itemKeys(kind string, items map[string]interface{}) []string {
+	var keys []string
+	if kind == "string-array" {
+		for i := 0; i < len(items); i++ {
+			if _, ok := items[strconv.Itoa(i)]; ok {
+				keys = append(keys, strconv.Itoa(i))
+			}
+		}
+		return keys
+	}
+
+	for _, quantity := range androidQuantities {
+		if _, ok := items[quantity]; ok {
+			keys = append(keys, quantity)
+		}
+	}
+	return keys
+}
+
+// isArrayContent reports whether every key of items is an array index
+func isArrayContent(items map[string]interface{}) bool {
+	for key := range items {
+		if _, err := strconv.Atoi(key); err != nil {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+// markupRegex matches the inline markup Android allows in strings, like <b> or <xliff:g>
+var markupRegex = regexp.MustCompile(`<[^>]+>`)
+
+// decodeAndroidString converts the inner XML of a resource into its text.
+// Strings with inline markup keep their markup and XML entities.
+func decodeAndroidString(inner string) string {
+	if !markupRegex.MatchString(inner) {
+		inner = html.UnescapeString(inner)
+	}
+
+	var builder strings.Builder
+	quoted := false
+	// Android collapses whitespace outside of quotes, a space is only written before the next character
+	pendingSpace := false
+	write := func(text string) {
+		if pendingSpace && builder.Len() > 0 {
+			builder.WriteByte(' ')
+		}
+		pendingSpace = false
+		builder.WriteString(text)
+	}
+
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\' && i+1 < len(inner):
+			i++
+			switch inner[i] {
+			case 'n':
+				write("\n")
+			case 't':
+				write("\t")
+			case 'u':
+				if i+4 < len(inner) {
+					if code, err := strconv.ParseUint(inner[i+1:i+5], 16, 32); err == nil {
+						write(string(rune(code)))
+						i += 4
+						break
+					}
+				}
+				write("u")
+			default:
+				write(inner[i : i+1])
+			}
+
+		case c == '"':
+			quoted = !quoted
+
+		case !quoted && (c == ' ' || c == '\n' || c == '\t' || c == '\r'):
+			pendingSpace = true
+
+		default:
+			write(inner[i : i+1])
+		}
+	}
+
+	return builder.String()
+}
+
+// encodeAndroidString converts text into the inner XML of a resource,
+// escaping what Android resources require. Inline markup is kept as is.
+func encodeAndroidString(text string) string {
+	hasMarkup := markupRegex.MatchString(text)
+
+	escape := func(part string) string {
+		part = strings.NewReplacer(
+			`\`, `\\`,
+			"\n", `\n`,
+			"\t", `\t`,
+			`'`, `\'`,
+			`"`, `\"`,
+		).Replace(part)
+		if !hasMarkup {
+			part = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(part)
+		}
+		return part
+	}
+
+	var result string
+	if hasMarkup {
+		last := 0
+		for _, match := range markupRegex.FindAllStringIndex(text, -1) {
+			result += escape(text[last:match[0]]) + text[match[0]:match[1]]
+			last = match[1]
+		}
+		result += escape(text[last:])
+	} else {
+		result = escape(text)
+	}
+
+	if strings.HasPrefix(result, "@") || strings.HasPrefix(result, "?") {
+		result = `\` + result
+	}
+	// Keep whitespace Android would otherwise collapse
+	if strings.HasPrefix(text, " ") || strings.HasSuffix(text, " ") || strings.Contains(text, "  ") {
+		result = `"` + result + `"`
+	}
+	return result
+}
@@ -0,0 +1,202 @@
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ARBManager implements FileManager for Flutter Application Resource Bundles.
+//
+// Files are named app_<locale>.arb, with locales written like Flutter does,
+// e.g. app_pt_BR.arb. Metadata like "@key" entries and "@@" global
+// attributes are kept as they are, except "@@locale" which is rewritten to
+// the locale of the file being written. Without a key order, existing keys
+// keep their position and new keys follow the order of the first file read.
+type ARBManager struct {
+	template *KeyOrder
+}
+
+// NewARBManager creates a new ARBManager
+func NewARBManager() *ARBManager {
+	return &ARBManager{}
+}
+
+// FilePath returns the app_<locale>.arb file of lang
+func (m *ARBManager) FilePath(folder, lang string, base bool) string {
+	return filepath.Join(folder, "app_"+ARBLocale(lang)+".arb")
+}
+
+// ARBLocale converts a language code like pt-BR to the locale used by ARB files, pt_BR
+func ARBLocale(lang string) string {
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// Write saves the content to an ARB file
+func (m *ARBManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to an ARB file with its keys in the order
+// of the document, "@@locale" always comes first
+func (m *ARBManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	content := doc.Content
+	order := doc.Order
+	if order == nil {
+		order = m.template
+		if data, err := os.ReadFile(filePath); err == nil {
+			if existing, err := readJSONOrder(data); err == nil {
+				order = mergeKeyOrders(existing, m.template)
+			}
+		}
+	}
+
+	// Every file declares its own locale
+	if _, ok := content["@@locale"]; ok {
+		if locale, ok := arbLocaleFromPath(filePath); ok {
+			updated := make(LanguageContent, len(content))
+			for key, value := range content {
+				updated[key] = value
+			}
+			updated["@@locale"] = locale
+			content = updated
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := writeJSONEntries(buffer, content, arbKeyOrder(content, order), order, ""); err != nil {
+		return fmt.Errorf("failed to marshal ARB content for %s: %w", filePath, err)
+	}
+	buffer.WriteString("\n")
+
+	// Write to file
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from an ARB file
+func (m *ARBManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from an ARB file along with the order of its keys
+func (m *ARBManager) ReadDocument(filePath string) (*Document, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	var content LanguageContent
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ARB from %s: %w", filePath, err)
+	}
+
+	order, err := readJSONOrder(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ARB from %s: %w", filePath, err)
+	}
+
+	if m.template == nil {
+		m.template = order
+	}
+
+	return &Document{Content: content, Order: order}, nil
+}
+
+// Exists checks if a file exists
+func (m *ARBManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// arbLocaleFromPath returns the locale of an app_<locale>.arb file
+func arbLocaleFromPath(filePath string) (string, bool) {
+	name := filepath.Base(filePath)
+	if !strings.HasPrefix(name, "app_") || !strings.HasSuffix(name, ".arb") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(name, "app_"), ".arb"), true
+}
+
+// arbKeyOrder returns the keys of content with "@@locale" first, then the
+// keys of order, followed by the others in sorted order. Sorted "@key"
+// entries follow the message they describe.
+func arbKeyOrder(content LanguageContent, order *KeyOrder) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if _, ok := content[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	add("@@locale")
+	if order != nil {
+		for _, key := range order.Keys {
+			add(key)
+		}
+	}
+
+	var otherKeys []string
+	for key := range content {
+		if !seen[key] {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Slice(otherKeys, func(i, j int) bool {
+		a, b := strings.TrimPrefix(otherKeys[i], "@"), strings.TrimPrefix(otherKeys[j], "@")
+		if a != b {
+			return a < b
+		}
+		return !IsMetadataKey(otherKeys[i])
+	})
+	for _, key := range otherKeys {
+		add(key)
+	}
+
+	return keys
+}
+
+// mergeKeyOrders returns the keys of first followed by those of second it
+// does not hold
+func mergeKeyOrders(first, second *KeyOrder) *KeyOrder {
+	merged := &KeyOrder{}
+	seen := make(map[string]bool)
+	for _, order := range []*KeyOrder{first, second} {
+		if order == nil {
+			continue
+		}
+		for _, key := range order.Keys {
+			if !seen[key] {
+				merged.Add(key, order.Child(key))
+				seen[key] = true
+			}
+		}
+	}
+	return merged
+}
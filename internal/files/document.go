@@ -0,0 +1,108 @@
+package files
+
+import "sort"
+
+// Document is the content of a translation file along with the order of
+// its keys, so files can be written with their keys in a given order
+type Document struct {
+	Content LanguageContent
+	// Order is the order of the keys of Content, nil when it is unknown
+	Order *KeyOrder
+}
+
+// KeyOrder is the order of the keys of an object, along with the order of
+// the keys of its nested objects. The orders of the items of an array are
+// keyed by their index.
+type KeyOrder struct {
+	Keys   []string
+	Nested map[string]*KeyOrder
+}
+
+// Add appends key, with the order of its nested object if it has one
+func (o *KeyOrder) Add(key string, nested *KeyOrder) {
+	o.Keys = append(o.Keys, key)
+	if nested != nil {
+		if o.Nested == nil {
+			o.Nested = make(map[string]*KeyOrder)
+		}
+		o.Nested[key] = nested
+	}
+}
+
+// Child returns the order of the nested object at key, or nil
+func (o *KeyOrder) Child(key string) *KeyOrder {
+	if o == nil {
+		return nil
+	}
+	return o.Nested[key]
+}
+
+// positions returns the position of every key
+func (o *KeyOrder) positions() map[string]int {
+	positions := make(map[string]int, len(o.Keys))
+	for i, key := range o.Keys {
+		if _, ok := positions[key]; !ok {
+			positions[key] = i
+		}
+	}
+	return positions
+}
+
+// OrderedKeys returns the keys of content in order, followed by the keys
+// missing from order in sorted order
+func OrderedKeys(content LanguageContent, order *KeyOrder) []string {
+	keys := make([]string, 0, len(content))
+	seen := make(map[string]bool, len(content))
+	if order != nil {
+		for _, key := range order.Keys {
+			if _, ok := content[key]; ok && !seen[key] {
+				keys = append(keys, key)
+				seen[key] = true
+			}
+		}
+	}
+
+	var otherKeys []string
+	for key := range content {
+		if !seen[key] {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	return append(keys, otherKeys...)
+}
+
+// reorder sorts items in place by the position of their key in order.
+// Items whose key is missing from order follow in their current order, and
+// items without a key, like comments or untranslatable resources, keep
+// their place in the slice. Nothing changes when order is nil.
+func reorder[T any](items []T, order *KeyOrder, key func(item T) (string, bool)) {
+	if order == nil {
+		return
+	}
+	positions := order.positions()
+
+	var slots []int
+	var keyed []T
+	for i, item := range items {
+		if _, ok := key(item); ok {
+			slots = append(slots, i)
+			keyed = append(keyed, item)
+		}
+	}
+
+	position := func(item T) int {
+		k, _ := key(item)
+		if p, ok := positions[k]; ok {
+			return p
+		}
+		return len(positions)
+	}
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return position(keyed[i]) < position(keyed[j])
+	})
+
+	for i, slot := range slots {
+		items[slot] = keyed[i]
+	}
+}
@@ -9,14 +9,60 @@ type LanguageContent map[string]interface{}
 type FileManager interface {
 	Write(filePath string, content LanguageContent) error
 	Read(filePath string) (LanguageContent, error)
+	// WriteDocument saves the content of doc with its keys in the order of doc
+	WriteDocument(filePath string, doc *Document) error
+	// ReadDocument loads the content of a file along with the order of its keys
+	ReadDocument(filePath string) (*Document, error)
 	Exists(filePath string) (bool, error)
 }
 
+// PathResolver is implemented by file managers whose files are not named
+// after their language, like Android resources
+type PathResolver interface {
+	// FilePath returns the path of the file of lang inside folder. base is
+	// set for the base language.
+	FilePath(folder, lang string, base bool) string
+}
+
+// MultiLanguageManager is implemented by file managers that keep every
+// language in a single document, like Apple String Catalogs. Read and Write
+// work on the source language of the document.
+type MultiLanguageManager interface {
+	FileManager
+	// ReadLanguage loads the content of lang, which is empty when the document has no translations for it
+	ReadLanguage(filePath, lang string) (LanguageContent, error)
+	// WriteLanguage replaces the content of lang, leaving the other languages untouched
+	WriteLanguage(filePath, lang string, content LanguageContent) error
+}
+
+// ContentShaper is implemented by file managers whose messages take another
+// shape in every language, like gettext plurals with one form per plural
+// form of the language
+type ContentShaper interface {
+	// ShapeContent returns the base content with its messages shaped like
+	// those of the file of lang at filePath
+	ShapeContent(filePath, lang string, base LanguageContent) LanguageContent
+}
+
 // Factory function to get a file manager
 func NewFileManager(fileType string) (FileManager, error) {
 	switch fileType {
 	case "json":
 		return NewJSONManager(), nil
+	case "yaml", "yml":
+		return NewYAMLManager(), nil
+	case "po":
+		return NewPOManager(), nil
+	case "xml":
+		return NewAndroidManager(), nil
+	case "strings":
+		return NewStringsManager(), nil
+	case "stringsdict":
+		return NewStringsdictManager(), nil
+	case "xcstrings":
+		return NewXCStringsManager(), nil
+	case "arb":
+		return NewARBManager(), nil
 	default:
 		return nil, fmt.Errorf("unsupported file type: %s", fileType)
 	}
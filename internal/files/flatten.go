@@ -0,0 +1,94 @@
+package files
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IsMetadataKey reports whether a key holds metadata (like "@key" entries in ARB) rather than a translation
+func IsMetadataKey(key string) bool {
+	return len(key) > 0 && key[0] == '@'
+}
+
+// AsContent converts a nested object value into LanguageContent
+func AsContent(value interface{}) (LanguageContent, bool) {
+	switch v := value.(type) {
+	case LanguageContent:
+		return v, true
+	case map[string]interface{}:
+		return LanguageContent(v), true
+	default:
+		return nil, false
+	}
+}
+
+// ArrayContent converts an array value into LanguageContent keyed by item
+// index, so its items can be traversed like the keys of a nested object
+func ArrayContent(value interface{}) (LanguageContent, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	content := make(LanguageContent, len(items))
+	for i, item := range items {
+		content[strconv.Itoa(i)] = item
+	}
+	return content, true
+}
+
+// ContentArray converts content keyed by item index back into an array of
+// length items, indexes missing from content are nil
+func ContentArray(content LanguageContent, length int) []interface{} {
+	items := make([]interface{}, length)
+	for i := range items {
+		items[i] = content[strconv.Itoa(i)]
+	}
+	return items
+}
+
+// keyEscaper escapes the path separator, and the escape character itself,
+// in keys
+var keyEscaper = strings.NewReplacer(`\`, `\\`, ".", `\.`)
+
+// KeyPath joins a nested key to the dotted path of its parent. Dots and
+// backslashes in key are escaped with a backslash, so a key like "a.b"
+// does not share its path with the key b nested in a.
+func KeyPath(prefix, key string) string {
+	return JoinPath(prefix, keyEscaper.Replace(key))
+}
+
+// JoinPath joins two paths built with KeyPath
+func JoinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	return prefix + "." + path
+}
+
+// Flatten returns every value of content that is not a nested object or an
+// array, keyed by its dotted path from KeyPath. Array items are keyed by
+// their index, like "steps.0". Metadata keys are skipped.
+func Flatten(content LanguageContent) map[string]interface{} {
+	result := make(map[string]interface{})
+	flatten(result, "", content)
+	return result
+}
+
+func flatten(result map[string]interface{}, prefix string, content LanguageContent) {
+	for key, value := range content {
+		if IsMetadataKey(key) {
+			continue
+		}
+
+		path := KeyPath(prefix, key)
+		if nested, ok := AsContent(value); ok {
+			flatten(result, path, nested)
+			continue
+		}
+		if items, ok := ArrayContent(value); ok {
+			flatten(result, path, items)
+			continue
+		}
+		result[path] = value
+	}
+}
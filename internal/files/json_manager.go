@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
 // JSONManager implements FileManager for JSON files
@@ -16,23 +17,25 @@ func NewJSONManager() *JSONManager {
 	return &JSONManager{}
 }
 
-// Write saves the content to a JSON file
+// Write saves the content to a JSON file with its keys in sorted order
 func (m *JSONManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a JSON file with its keys in the order
+// of the document, keys missing from it follow in sorted order
+func (m *JSONManager) WriteDocument(filePath string, doc *Document) error {
 	// Ensure directory exists
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-
 	buffer := &bytes.Buffer{}
-	encoder := json.NewEncoder(buffer)
-	encoder.SetEscapeHTML(false)
-	encoder.SetIndent("", "  ")
-	
-	if err := encoder.Encode(content); err != nil {
+	if err := writeJSONObject(buffer, doc.Content, doc.Order, ""); err != nil {
 		return fmt.Errorf("failed to marshal JSON content for %s: %w", filePath, err)
 	}
+	buffer.WriteString("\n")
 
 	// Write to file
 	err := os.WriteFile(filePath, buffer.Bytes(), 0644)
@@ -45,6 +48,15 @@ func (m *JSONManager) Write(filePath string, content LanguageContent) error {
 
 // Read loads content from a JSON file
 func (m *JSONManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a JSON file along with the order of its keys
+func (m *JSONManager) ReadDocument(filePath string) (*Document, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
@@ -55,7 +67,12 @@ func (m *JSONManager) Read(filePath string) (LanguageContent, error) {
 		return nil, fmt.Errorf("failed to unmarshal JSON from %s: %w", filePath, err)
 	}
 
-	return content, nil
+	order, err := readJSONOrder(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON from %s: %w", filePath, err)
+	}
+
+	return &Document{Content: content, Order: order}, nil
 }
 
 // Exists checks if a file exists
@@ -69,4 +86,144 @@ func (m *JSONManager) Exists(filePath string) (bool, error) {
 	}
 	
 	return !info.IsDir(), nil // Return true if it exists and is not a directory
-}
\ No newline at end of file
+}
+
+// writeJSONObject writes content as an indented JSON object with its keys in order
+func writeJSONObject(buffer *bytes.Buffer, content LanguageContent, order *KeyOrder, indent string) error {
+	return writeJSONEntries(buffer, content, OrderedKeys(content, order), order, indent)
+}
+
+// writeJSONEntries writes the given keys of content as an indented JSON
+// object. Nested objects are written in the order recorded for them.
+func writeJSONEntries(buffer *bytes.Buffer, content LanguageContent, keys []string, order *KeyOrder, indent string) error {
+	if len(keys) == 0 {
+		buffer.WriteString("{}")
+		return nil
+	}
+
+	buffer.WriteString("{")
+	for i, key := range keys {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n" + indent + "  ")
+		if err := encodeJSONValue(buffer, key, ""); err != nil {
+			return err
+		}
+		buffer.WriteString(": ")
+
+		if err := writeJSONValue(buffer, content[key], order.Child(key), indent+"  "); err != nil {
+			return err
+		}
+	}
+	buffer.WriteString("\n" + indent + "}")
+	return nil
+}
+
+// writeJSONValue writes value indented for its depth, with the objects it
+// holds, in arrays too, written in the order recorded for them
+func writeJSONValue(buffer *bytes.Buffer, value interface{}, order *KeyOrder, indent string) error {
+	if nested, ok := AsContent(value); ok {
+		return writeJSONObject(buffer, nested, order, indent)
+	}
+	items, ok := value.([]interface{})
+	if !ok || len(items) == 0 {
+		return encodeJSONValue(buffer, value, indent)
+	}
+
+	buffer.WriteString("[")
+	for i, item := range items {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n" + indent + "  ")
+		if err := writeJSONValue(buffer, item, order.Child(strconv.Itoa(i)), indent+"  "); err != nil {
+			return err
+		}
+	}
+	buffer.WriteString("\n" + indent + "]")
+	return nil
+}
+
+// encodeJSONValue writes value as JSON indented for its depth, without escaping HTML
+func encodeJSONValue(buffer *bytes.Buffer, value interface{}, indent string) error {
+	encoded := &bytes.Buffer{}
+	encoder := json.NewEncoder(encoded)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(indent, "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	buffer.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	return nil
+}
+
+// readJSONOrder returns the order of the keys of the JSON object in data
+// and of its nested objects
+func readJSONOrder(data []byte) (*KeyOrder, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, fmt.Errorf("content is not a JSON object")
+	}
+	return decodeJSONObjectOrder(decoder)
+}
+
+// decodeJSONObjectOrder reads the rest of an object whose opening brace was read
+func decodeJSONObjectOrder(decoder *json.Decoder) (*KeyOrder, error) {
+	order := &KeyOrder{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected token %v", token)
+		}
+
+		nested, err := decodeJSONValueOrder(decoder)
+		if err != nil {
+			return nil, err
+		}
+		order.Add(key, nested)
+	}
+
+	// Closing brace
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// decodeJSONValueOrder reads a value, returning its order when it is an
+// object, or the orders of its items keyed by index when it is an array
+func decodeJSONValueOrder(decoder *json.Decoder) (*KeyOrder, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		return decodeJSONObjectOrder(decoder)
+	case json.Delim('['):
+		order := &KeyOrder{}
+		for index := 0; decoder.More(); index++ {
+			nested, err := decodeJSONValueOrder(decoder)
+			if err != nil {
+				return nil, err
+			}
+			order.Add(strconv.Itoa(index), nested)
+		}
+		// Closing bracket
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,131 @@
+package files
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pluralCategories holds the CLDR cardinal plural categories of the
+// languages that differ from the English one and other
+var pluralCategories = map[string][]string{
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+	"be": {"one", "few", "many", "other"},
+	"bs": {"one", "few", "other"},
+	"ca": {"one", "many", "other"},
+	"cs": {"one", "few", "many", "other"},
+	"cy": {"zero", "one", "two", "few", "many", "other"},
+	"es": {"one", "many", "other"},
+	"fr": {"one", "many", "other"},
+	"ga": {"one", "two", "few", "many", "other"},
+	"gd": {"one", "two", "few", "other"},
+	"he": {"one", "two", "other"},
+	"hr": {"one", "few", "other"},
+	"id": {"other"},
+	"it": {"one", "many", "other"},
+	"ja": {"other"},
+	"km": {"other"},
+	"ko": {"other"},
+	"lo": {"other"},
+	"lt": {"one", "few", "many", "other"},
+	"lv": {"zero", "one", "other"},
+	"ms": {"other"},
+	"mt": {"one", "two", "few", "many", "other"},
+	"my": {"other"},
+	"pl": {"one", "few", "many", "other"},
+	"pt": {"one", "many", "other"},
+	"ro": {"one", "few", "other"},
+	"ru": {"one", "few", "many", "other"},
+	"sk": {"one", "few", "many", "other"},
+	"sl": {"one", "two", "few", "other"},
+	"sr": {"one", "few", "other"},
+	"th": {"other"},
+	"uk": {"one", "few", "many", "other"},
+	"vi": {"other"},
+	"zh": {"other"},
+}
+
+// PluralCategories returns the CLDR cardinal plural categories of a
+// language, ordered from zero to other
+func PluralCategories(lang string) []string {
+	primary, _, _ := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	if categories, ok := pluralCategories[strings.ToLower(primary)]; ok {
+		return categories
+	}
+	return []string{"one", "other"}
+}
+
+// isPluralCategory reports whether key names a CLDR plural category
+func isPluralCategory(key string) bool {
+	for _, quantity := range androidQuantities {
+		if key == quantity {
+			return true
+		}
+	}
+	return false
+}
+
+// isPluralContent reports whether every key of forms, metadata aside, is a
+// plural category
+func isPluralContent(forms map[string]interface{}) bool {
+	found := false
+	for key := range forms {
+		if IsMetadataKey(key) {
+			continue
+		}
+		if !isPluralCategory(key) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// shapePlural returns the forms of a plural message for the plural
+// categories of lang. Categories the source lacks start from its other
+// form and are described, so translators that take descriptions know
+// which numbers they are for. Source categories of keep stay even when
+// lang does not use them, like the explicit zero of Apple plurals.
+func shapePlural(forms map[string]interface{}, lang string, keep ...string) map[string]interface{} {
+	categories := PluralCategories(lang)
+	fallback, ok := forms["other"]
+	if !ok {
+		for _, quantity := range androidQuantities {
+			if form, ok := forms[quantity]; ok {
+				fallback = form
+				break
+			}
+		}
+	}
+
+	shaped := make(map[string]interface{}, len(categories))
+	for _, category := range keep {
+		if form, ok := forms[category]; ok {
+			shaped[category] = form
+		}
+	}
+	for _, category := range categories {
+		if form, ok := forms[category]; ok {
+			shaped[category] = form
+			continue
+		}
+		shaped[category] = fallback
+		shaped["@"+category] = map[string]interface{}{
+			"description": fmt.Sprintf("Plural category %q of %s, translated for the numbers CLDR puts in that category", category, lang),
+		}
+	}
+	return shaped
+}
+
+// shapeApplePlurals returns content with its plural objects shaped for
+// lang, keeping the zero form Apple plurals use in every language
+func shapeApplePlurals(content LanguageContent, lang string) LanguageContent {
+	shaped := make(LanguageContent, len(content))
+	for key, value := range content {
+		if forms, ok := AsContent(value); ok && isPluralContent(forms) {
+			shaped[key] = shapePlural(forms, lang, "zero")
+		} else {
+			shaped[key] = value
+		}
+	}
+	return shaped
+}
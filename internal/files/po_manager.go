@@ -0,0 +1,794 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContextSeparator joins the msgctxt and msgid of a gettext message into a
+// single key, like gettext does internally
+const ContextSeparator = "\x04"
+
+// POManager implements FileManager for GNU gettext PO files.
+//
+// Every message is keyed by its msgid, prefixed with its msgctxt and
+// ContextSeparator when it has one. Untranslated and fuzzy messages read as
+// their msgid, which is what gettext shows for them at runtime. Plural
+// messages are nested objects holding one form per msgstr[n], as many as
+// the Plural-Forms header of the file declares; ShapeContent gives the
+// base content of every language those forms so each one is translated.
+//
+// Writing to an existing file keeps its header and comments, and its
+// message order unless the content is written with an order of its own.
+// New files are generated from the .pot template next to them, or
+// from the first file read when there is none, with the Language and
+// Plural-Forms headers of their own language.
+type POManager struct {
+	template *poCatalog
+}
+
+// NewPOManager creates a new POManager
+func NewPOManager() *POManager {
+	return &POManager{}
+}
+
+// Write saves the content to a PO file
+func (m *POManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a PO file with its messages in the order of the document
+func (m *POManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	lang := languageFromPath(filePath)
+	template := m.findTemplate(dir)
+
+	catalog, err := readPOCatalog(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		catalog = newPOCatalog(template, lang)
+	}
+
+	if err := catalog.update(doc.Content, template, doc.Order); err != nil {
+		return fmt.Errorf("failed to marshal PO content for %s: %w", filePath, err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, catalog.bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from a PO file
+func (m *POManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a PO file along with the order of its messages
+func (m *POManager) ReadDocument(filePath string) (*Document, error) {
+	catalog, err := readPOCatalog(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.template == nil {
+		m.template = catalog
+	}
+
+	order := &KeyOrder{}
+	for _, entry := range catalog.entries {
+		if entry.isMessage && !entry.isHeader() {
+			order.Add(entry.key(), nil)
+		}
+	}
+
+	return &Document{Content: catalog.content(), Order: order}, nil
+}
+
+// ShapeContent implements ContentShaper. Plural messages get one form per
+// plural form of the file of lang, or of lang itself for new files: the
+// singular source for the first form and the plural source for the others,
+// or the plural source alone for languages with a single form. Forms after
+// the second are described with the Plural-Forms rule, so translators that
+// take descriptions know which numbers each one is used for.
+func (m *POManager) ShapeContent(filePath, lang string, base LanguageContent) LanguageContent {
+	forms := PluralForms(lang)
+	if catalog, err := readPOCatalog(filePath); err == nil {
+		forms = catalog.pluralForms()
+	}
+	count, ok := parseNPlurals(forms)
+	if !ok {
+		count = 2
+	}
+
+	shaped := make(LanguageContent, len(base))
+	for key, value := range base {
+		nested, ok := AsContent(value)
+		if !ok {
+			shaped[key] = value
+			continue
+		}
+		singular, _ := nested["0"].(string)
+		plural, _ := nested["1"].(string)
+
+		message := make(map[string]interface{}, count)
+		for i := 0; i < count; i++ {
+			form := strconv.Itoa(i)
+			if i == 0 && count > 1 {
+				message[form] = singular
+				continue
+			}
+			message[form] = plural
+			if count > 2 {
+				message["@"+form] = map[string]interface{}{
+					"description": fmt.Sprintf("Plural form %d of the gettext rule %q, translated for the numbers the rule maps to %d", i, forms, i),
+				}
+			}
+		}
+		shaped[key] = message
+	}
+	return shaped
+}
+
+// Exists checks if a file exists
+func (m *POManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// findTemplate returns the first .pot file of dir, falling back to the first file read
+func (m *POManager) findTemplate(dir string) *poCatalog {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.pot"))
+	sort.Strings(matches)
+	for _, match := range matches {
+		if catalog, err := readPOCatalog(match); err == nil {
+			return catalog
+		}
+	}
+	return m.template
+}
+
+// poEntry is a single message of a PO file, or a block of comments, like
+// obsolete "#~" messages, that is not followed by a message
+type poEntry struct {
+	// comments holds the raw comment lines in file order
+	comments   []string
+	isMessage  bool
+	hasContext bool
+	context    string
+	id         string
+	idPlural   string
+	str        string
+	plurals    []string
+}
+
+// key returns the content key of the message
+func (e *poEntry) key() string {
+	if e.hasContext {
+		return e.context + ContextSeparator + e.id
+	}
+	return e.id
+}
+
+// isHeader reports whether the entry is the PO header
+func (e *poEntry) isHeader() bool {
+	return e.isMessage && !e.hasContext && e.id == ""
+}
+
+// isPlural reports whether the message has plural forms
+func (e *poEntry) isPlural() bool {
+	return e.idPlural != "" || len(e.plurals) > 0
+}
+
+// isFuzzy reports whether the message is flagged as fuzzy
+func (e *poEntry) isFuzzy() bool {
+	for _, comment := range e.comments {
+		if strings.HasPrefix(comment, "#,") && containsFlag(comment, "fuzzy") {
+			return true
+		}
+	}
+	return false
+}
+
+// clearFuzzy removes the fuzzy flag once the message has a new translation
+func (e *poEntry) clearFuzzy() {
+	comments := e.comments[:0]
+	for _, comment := range e.comments {
+		if strings.HasPrefix(comment, "#,") && containsFlag(comment, "fuzzy") {
+			var flags []string
+			for _, flag := range strings.Split(strings.TrimPrefix(comment, "#,"), ",") {
+				if flag = strings.TrimSpace(flag); flag != "" && flag != "fuzzy" {
+					flags = append(flags, flag)
+				}
+			}
+			if len(flags) == 0 {
+				continue
+			}
+			comment = "#, " + strings.Join(flags, ", ")
+		}
+		comments = append(comments, comment)
+	}
+	e.comments = comments
+}
+
+// containsFlag reports whether a "#," comment line holds flag
+func containsFlag(comment, flag string) bool {
+	for _, item := range strings.Split(strings.TrimPrefix(comment, "#,"), ",") {
+		if strings.TrimSpace(item) == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// poCatalog is a parsed PO file
+type poCatalog struct {
+	entries []*poEntry
+}
+
+// readPOCatalog parses a PO or POT file
+func readPOCatalog(filePath string) (*poCatalog, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	catalog, err := parsePO(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PO file %s: %w", filePath, err)
+	}
+	return catalog, nil
+}
+
+// parsePO parses the content of a PO file
+func parsePO(data []byte) (*poCatalog, error) {
+	catalog := &poCatalog{}
+	entry := &poEntry{}
+	// target points to the string continuation lines are appended to
+	var target *string
+
+	flush := func() {
+		if entry.isMessage || len(entry.comments) > 0 {
+			catalog.entries = append(catalog.entries, entry)
+		}
+		entry = &poEntry{}
+		target = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		switch {
+		case line == "":
+			flush()
+
+		case strings.HasPrefix(line, "#"):
+			// Comments start a new entry when they follow a message
+			if entry.isMessage {
+				flush()
+			}
+			entry.comments = append(entry.comments, line)
+
+		case strings.HasPrefix(line, `"`):
+			if target == nil {
+				return nil, fmt.Errorf("line %d: unexpected string", lineNumber)
+			}
+			value, err := unquotePO(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			*target += value
+
+		default:
+			keyword, rest, _ := strings.Cut(line, " ")
+			value, err := unquotePO(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+
+			switch {
+			case keyword == "msgctxt":
+				if entry.isMessage {
+					flush()
+				}
+				entry.hasContext = true
+				entry.context = value
+				target = &entry.context
+
+			case keyword == "msgid":
+				if entry.isMessage {
+					flush()
+				}
+				entry.isMessage = true
+				entry.id = value
+				target = &entry.id
+
+			case keyword == "msgid_plural":
+				entry.idPlural = value
+				target = &entry.idPlural
+
+			case keyword == "msgstr":
+				entry.str = value
+				target = &entry.str
+
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				index, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+				if err != nil || index < 0 || index != len(entry.plurals) {
+					return nil, fmt.Errorf("line %d: invalid plural index in %s", lineNumber, keyword)
+				}
+				entry.plurals = append(entry.plurals, value)
+				target = &entry.plurals[index]
+
+			default:
+				return nil, fmt.Errorf("line %d: unknown keyword %q", lineNumber, keyword)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return catalog, nil
+}
+
+// newPOCatalog creates the catalog of a new language file from a template
+func newPOCatalog(template *poCatalog, lang string) *poCatalog {
+	header := &poEntry{
+		isMessage: true,
+		str:       "Content-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\n",
+	}
+	if template != nil {
+		if templateHeader := template.header(); templateHeader != nil {
+			header.comments = append([]string(nil), templateHeader.comments...)
+			header.str = templateHeader.str
+		}
+	}
+
+	header.clearFuzzy()
+	header.str = setHeaderField(header.str, "Language", lang)
+	header.str = setHeaderField(header.str, "Plural-Forms", PluralForms(lang))
+
+	return &poCatalog{entries: []*poEntry{header}}
+}
+
+// header returns the header entry of the catalog, if any
+func (c *poCatalog) header() *poEntry {
+	for _, entry := range c.entries {
+		if entry.isHeader() {
+			return entry
+		}
+	}
+	return nil
+}
+
+// pluralForms returns the Plural-Forms declared by the header, or those of
+// the language of the header when it declares none
+func (c *poCatalog) pluralForms() string {
+	header := c.header()
+	if header == nil {
+		return PluralForms("")
+	}
+	if forms := headerField(header.str, "Plural-Forms"); nPluralsRegex.MatchString(forms) {
+		return forms
+	}
+	return PluralForms(headerField(header.str, "Language"))
+}
+
+// pluralCount returns the number of plural forms declared by the header
+func (c *poCatalog) pluralCount() int {
+	if count, ok := parseNPlurals(c.pluralForms()); ok {
+		return count
+	}
+	return 2
+}
+
+// content converts the messages of the catalog into LanguageContent
+func (c *poCatalog) content() LanguageContent {
+	content := make(LanguageContent)
+	pluralCount := c.pluralCount()
+
+	for _, entry := range c.entries {
+		if !entry.isMessage || entry.isHeader() {
+			continue
+		}
+
+		translated := !entry.isFuzzy()
+		if !entry.isPlural() {
+			if translated && entry.str != "" {
+				content[entry.key()] = entry.str
+			} else {
+				content[entry.key()] = entry.id
+			}
+			continue
+		}
+
+		forms := make(map[string]interface{}, pluralCount)
+		for i := 0; i < pluralCount; i++ {
+			// Untranslated forms read as their source, like ShapeContent makes them
+			form := entry.idPlural
+			if i == 0 && pluralCount > 1 {
+				form = entry.id
+			}
+			if translated && i < len(entry.plurals) && entry.plurals[i] != "" {
+				form = entry.plurals[i]
+			}
+			forms[strconv.Itoa(i)] = form
+		}
+		content[entry.key()] = forms
+	}
+
+	return content
+}
+
+// update makes the catalog hold content. Existing messages keep their
+// comments, removed messages are dropped and new messages are appended in
+// the order of the template, followed by the others in sorted order. With
+// an order, messages are then moved to their place in it; without one,
+// existing messages keep their position.
+func (c *poCatalog) update(content LanguageContent, template *poCatalog, order *KeyOrder) error {
+	pluralCount := c.pluralCount()
+	seen := make(map[string]bool)
+	entries := make([]*poEntry, 0, len(c.entries))
+
+	for _, entry := range c.entries {
+		if !entry.isMessage || entry.isHeader() {
+			entries = append(entries, entry)
+			continue
+		}
+
+		key := entry.key()
+		value, ok := content[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if err := entry.set(value, pluralCount); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	var newKeys []string
+	if template != nil {
+		for _, templateEntry := range template.entries {
+			key := templateEntry.key()
+			if _, ok := content[key]; ok && templateEntry.isMessage && !templateEntry.isHeader() && !seen[key] {
+				newKeys = append(newKeys, key)
+				seen[key] = true
+			}
+		}
+	}
+	var otherKeys []string
+	for key := range content {
+		if !seen[key] && key != "" {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	newKeys = append(newKeys, otherKeys...)
+
+	for _, key := range newKeys {
+		entry := newPOEntry(key, template)
+		if err := entry.set(content[key], pluralCount); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	reorder(entries, order, func(entry *poEntry) (string, bool) {
+		return entry.key(), entry.isMessage && !entry.isHeader()
+	})
+
+	c.entries = entries
+	return nil
+}
+
+// newPOEntry creates a message for key, copying its source and the
+// extracted comments, references and flags of the template message
+func newPOEntry(key string, template *poCatalog) *poEntry {
+	entry := &poEntry{isMessage: true, id: key}
+	if context, id, ok := strings.Cut(key, ContextSeparator); ok {
+		entry.hasContext = true
+		entry.context = context
+		entry.id = id
+	}
+
+	if template == nil {
+		return entry
+	}
+	for _, templateEntry := range template.entries {
+		if !templateEntry.isMessage || templateEntry.key() != key {
+			continue
+		}
+		entry.idPlural = templateEntry.idPlural
+		for _, comment := range templateEntry.comments {
+			// Translator comments belong to the language of the template
+			if strings.HasPrefix(comment, "#.") || strings.HasPrefix(comment, "#:") || strings.HasPrefix(comment, "#,") {
+				entry.comments = append(entry.comments, comment)
+			}
+		}
+		break
+	}
+	return entry
+}
+
+// set stores the translation of a message, clearing its fuzzy flag when it changed
+func (e *poEntry) set(value interface{}, pluralCount int) error {
+	if nested, ok := AsContent(value); ok {
+		if e.idPlural == "" {
+			e.idPlural = e.id
+		}
+
+		// Forms missing from the content keep their translation, or stay empty
+		// so gettext shows the source, since no other form translates them
+		plurals := make([]string, pluralCount)
+		for i := range plurals {
+			if form, ok := nested[strconv.Itoa(i)].(string); ok {
+				plurals[i] = form
+			} else if i < len(e.plurals) {
+				plurals[i] = e.plurals[i]
+			}
+		}
+		if !equalStrings(plurals, e.plurals) {
+			e.clearFuzzy()
+		}
+		e.plurals = plurals
+		e.str = ""
+		return nil
+	}
+
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case nil:
+		text = ""
+	case []interface{}:
+		return fmt.Errorf("unsupported array value for msgid %q", e.id)
+	default:
+		text = fmt.Sprint(v)
+	}
+
+	if text != e.str {
+		e.clearFuzzy()
+	}
+	e.str = text
+	e.plurals = nil
+	return nil
+}
+
+// bytes renders the catalog as a PO file
+func (c *poCatalog) bytes() []byte {
+	buffer := &bytes.Buffer{}
+
+	for i, entry := range c.entries {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		for _, comment := range entry.comments {
+			buffer.WriteString(comment + "\n")
+		}
+		if !entry.isMessage {
+			continue
+		}
+
+		if entry.hasContext {
+			writePOString(buffer, "msgctxt", entry.context)
+		}
+		writePOString(buffer, "msgid", entry.id)
+		if entry.isPlural() {
+			writePOString(buffer, "msgid_plural", entry.idPlural)
+			for index, plural := range entry.plurals {
+				writePOString(buffer, fmt.Sprintf("msgstr[%d]", index), plural)
+			}
+		} else {
+			writePOString(buffer, "msgstr", entry.str)
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+// writePOString writes a keyword and its quoted value, splitting multiline values after each newline
+func writePOString(buffer *bytes.Buffer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) <= 1 {
+		fmt.Fprintf(buffer, "%s %s\n", keyword, quotePO(value))
+		return
+	}
+
+	fmt.Fprintf(buffer, "%s \"\"\n", keyword)
+	for _, line := range lines {
+		buffer.WriteString(quotePO(line) + "\n")
+	}
+}
+
+// poEscapes maps the characters escaped in PO strings to their escape letter
+var poEscapes = map[rune]byte{'\\': '\\', '"': '"', '\n': 'n', '\t': 't', '\r': 'r', '\a': 'a', '\b': 'b', '\f': 'f', '\v': 'v'}
+
+// quotePO quotes a PO string
+func quotePO(value string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, r := range value {
+		if escape, ok := poEscapes[r]; ok {
+			builder.WriteByte('\\')
+			builder.WriteByte(escape)
+			continue
+		}
+		builder.WriteRune(r)
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// unquotePO parses a quoted PO string
+func unquotePO(quoted string) (string, error) {
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", quoted)
+	}
+
+	var builder strings.Builder
+	value := quoted[1 : len(quoted)-1]
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			if value[i] == '"' {
+				return "", fmt.Errorf("unescaped quote in %s", quoted)
+			}
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		if i == len(value) {
+			return "", fmt.Errorf("invalid escape at the end of %s", quoted)
+		}
+		found := false
+		for r, escape := range poEscapes {
+			if value[i] == escape {
+				builder.WriteRune(r)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unknown escape \\%c in %s", value[i], quoted)
+		}
+	}
+	return builder.String(), nil
+}
+
+// headerField returns the value of a field of the PO header
+func headerField(header, name string) string {
+	for _, line := range strings.Split(header, "\n") {
+		if field, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(field), name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// setHeaderField sets a field of the PO header, appending it when missing
+func setHeaderField(header, name, value string) string {
+	lines := strings.SplitAfter(header, "\n")
+	for i, line := range lines {
+		if field, _, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(field), name) {
+			lines[i] = name + ": " + value + "\n"
+			return strings.Join(lines, "")
+		}
+	}
+
+	if header != "" && !strings.HasSuffix(header, "\n") {
+		header += "\n"
+	}
+	return header + name + ": " + value + "\n"
+}
+
+var nPluralsRegex = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// parseNPlurals returns the number of forms declared by a Plural-Forms value
+func parseNPlurals(pluralForms string) (int, bool) {
+	match := nPluralsRegex.FindStringSubmatch(pluralForms)
+	if match == nil {
+		return 0, false
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil || count < 1 {
+		return 0, false
+	}
+	return count, true
+}
+
+// pluralForms holds the gettext Plural-Forms of the languages that differ from the English rule
+var pluralForms = map[string]string{
+	"ar": "nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+	"be": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"bs": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"cs": "nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);",
+	"cy": "nplurals=4; plural=(n==1 ? 0 : n==2 ? 1 : n!=8 && n!=11 ? 2 : 3);",
+	"fr": "nplurals=2; plural=(n > 1);",
+	"ga": "nplurals=5; plural=(n==1 ? 0 : n==2 ? 1 : n<7 ? 2 : n<11 ? 3 : 4);",
+	"hr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"id": "nplurals=1; plural=0;",
+	"ja": "nplurals=1; plural=0;",
+	"km": "nplurals=1; plural=0;",
+	"ko": "nplurals=1; plural=0;",
+	"lt": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"lv": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);",
+	"ms": "nplurals=1; plural=0;",
+	"pl": "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"ro": "nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);",
+	"ru": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sk": "nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);",
+	"sl": "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);",
+	"sr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"th": "nplurals=1; plural=0;",
+	"uk": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"vi": "nplurals=1; plural=0;",
+	"zh": "nplurals=1; plural=0;",
+}
+
+// PluralForms returns the gettext Plural-Forms header value of a language
+func PluralForms(lang string) string {
+	primary, _, _ := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	if forms, ok := pluralForms[strings.ToLower(primary)]; ok {
+		return forms
+	}
+	return "nplurals=2; plural=(n != 1);"
+}
+
+// equalStrings reports whether two string slices hold the same values
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,451 @@
+package files
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// StringsManager implements FileManager for Apple .strings files.
+//
+// Files live in <lang>.lproj/Localizable.strings. UTF-8 and UTF-16 files
+// with a byte order mark are supported and keep their encoding; new files
+// use the encoding of the first file read. Writing to an existing file
+// keeps its comments, and its key order unless the content is written with
+// an order of its own. New keys get the comments of the first file read.
+type StringsManager struct {
+	template *stringsDocument
+}
+
+// NewStringsManager creates a new StringsManager
+func NewStringsManager() *StringsManager {
+	return &StringsManager{}
+}
+
+// FilePath returns the Localizable.strings file of the lproj folder of lang
+func (m *StringsManager) FilePath(folder, lang string, base bool) string {
+	return lprojPath(folder, lang, "Localizable.strings")
+}
+
+// Write saves the content to a .strings file
+func (m *StringsManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a .strings file with its keys in the order of the document
+func (m *StringsManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	document, err := readStringsDocument(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		document = &stringsDocument{encoding: "utf-8", trailer: "\n"}
+		if m.template != nil {
+			document.encoding = m.template.encoding
+		}
+	}
+
+	document.update(doc.Content, m.template, doc.Order)
+
+	// Write to file
+	if err := os.WriteFile(filePath, document.bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from a .strings file
+func (m *StringsManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a .strings file along with the order of its keys
+func (m *StringsManager) ReadDocument(filePath string) (*Document, error) {
+	document, err := readStringsDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.template == nil {
+		m.template = document
+	}
+
+	content := make(LanguageContent)
+	order := &KeyOrder{}
+	for _, entry := range document.entries {
+		content[entry.key] = entry.value
+		order.Add(entry.key, nil)
+	}
+	return &Document{Content: content, Order: order}, nil
+}
+
+// Exists checks if a file exists
+func (m *StringsManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// lprojPath returns the path of a localized file inside the lproj folder of lang
+func lprojPath(folder, lang, name string) string {
+	return filepath.Join(folder, lang+".lproj", name)
+}
+
+// stringsDocument is a parsed .strings file
+type stringsDocument struct {
+	// encoding is "utf-8", "utf-16le" or "utf-16be"
+	encoding string
+	entries  []*stringsEntry
+	// trailer holds the whitespace and comments after the last entry
+	trailer string
+}
+
+// stringsEntry is a single "key" = "value"; pair
+type stringsEntry struct {
+	// leading holds the whitespace and comments written before the pair
+	leading string
+	// raw holds the pair as written in the file
+	raw   string
+	key   string
+	value string
+}
+
+// readStringsDocument parses a .strings file
+func readStringsDocument(filePath string) (*stringsDocument, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	text, encoding, err := decodeStringsData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", filePath, err)
+	}
+
+	document, err := parseStrings(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse strings file %s: %w", filePath, err)
+	}
+	document.encoding = encoding
+	return document, nil
+}
+
+// decodeStringsData converts the content of a .strings file to UTF-8
+func decodeStringsData(data []byte) (string, string, error) {
+	var order binary.ByteOrder
+	encoding := "utf-8"
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		order, encoding = binary.LittleEndian, "utf-16le"
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		order, encoding = binary.BigEndian, "utf-16be"
+	default:
+		data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+		if !utf8.Valid(data) {
+			return "", "", fmt.Errorf("content is neither UTF-8 nor UTF-16 with a byte order mark")
+		}
+		return string(data), encoding, nil
+	}
+
+	data = data[2:]
+	if len(data)%2 != 0 {
+		return "", "", fmt.Errorf("truncated UTF-16 content")
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(units)), encoding, nil
+}
+
+// parseStrings parses the UTF-8 content of a .strings file
+func parseStrings(text string) (*stringsDocument, error) {
+	document := &stringsDocument{}
+	p := &stringsParser{text: text}
+
+	for {
+		leadingStart := p.pos
+		if err := p.skipSpaceAndComments(); err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.text) {
+			document.trailer = text[leadingStart:]
+			return document, nil
+		}
+
+		start := p.pos
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		value, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+
+		document.entries = append(document.entries, &stringsEntry{
+			leading: text[leadingStart:start],
+			raw:     text[start:p.pos],
+			key:     key,
+			value:   value,
+		})
+	}
+}
+
+// stringsParser reads the tokens of a .strings file
+type stringsParser struct {
+	text string
+	pos  int
+}
+
+// skipSpaceAndComments moves past whitespace and comments
+func (p *stringsParser) skipSpaceAndComments() error {
+	for p.pos < len(p.text) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(p.text[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.text[p.pos:], "/*"):
+			end := strings.Index(p.text[p.pos+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("unterminated comment at offset %d", p.pos)
+			}
+			p.pos += end + 4
+		case strings.HasPrefix(p.text[p.pos:], "//"):
+			end := strings.IndexByte(p.text[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.text)
+			} else {
+				p.pos += end + 1
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// expect moves past the next token, which must be c
+func (p *stringsParser) expect(c byte) error {
+	if err := p.skipSpaceAndComments(); err != nil {
+		return err
+	}
+	if p.pos == len(p.text) || p.text[p.pos] != c {
+		return fmt.Errorf("expected '%c' at offset %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// parseString reads a quoted string or an unquoted word
+func (p *stringsParser) parseString() (string, error) {
+	if err := p.skipSpaceAndComments(); err != nil {
+		return "", err
+	}
+	if p.pos == len(p.text) {
+		return "", fmt.Errorf("unexpected end of file")
+	}
+
+	if p.text[p.pos] != '"' {
+		start := p.pos
+		for p.pos < len(p.text) && !strings.ContainsRune(" \t\r\n=;\"", rune(p.text[p.pos])) {
+			p.pos++
+		}
+		if p.pos == start {
+			return "", fmt.Errorf("expected a string at offset %d", p.pos)
+		}
+		return p.text[start:p.pos], nil
+	}
+
+	var builder strings.Builder
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		c := p.text[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return builder.String(), nil
+
+		case c == '\\' && p.pos+1 < len(p.text):
+			p.pos++
+			switch escaped := p.text[p.pos]; escaped {
+			case 'n':
+				builder.WriteByte('\n')
+			case 't':
+				builder.WriteByte('\t')
+			case 'r':
+				builder.WriteByte('\r')
+			case 'U', 'u':
+				if p.pos+4 < len(p.text) {
+					if code, err := strconv.ParseUint(p.text[p.pos+1:p.pos+5], 16, 32); err == nil {
+						builder.WriteRune(rune(code))
+						p.pos += 4
+						continue
+					}
+				}
+				builder.WriteByte(escaped)
+			default:
+				builder.WriteByte(escaped)
+			}
+
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// update makes the document hold content. Existing pairs keep their
+// comments, removed pairs are dropped and new pairs are appended in the
+// order of the template, followed by the others in sorted order. With an
+// order, pairs are then moved to their place in it; without one, existing
+// pairs keep their position.
+func (d *stringsDocument) update(content LanguageContent, template *stringsDocument, order *KeyOrder) {
+	seen := make(map[string]bool)
+	entries := make([]*stringsEntry, 0, len(d.entries))
+
+	for _, entry := range d.entries {
+		value, ok := content[entry.key]
+		if !ok || seen[entry.key] {
+			continue
+		}
+		seen[entry.key] = true
+
+		if text := fmt.Sprint(value); text != entry.value {
+			entry.value = text
+			entry.raw = formatStringsPair(entry.key, text)
+		}
+		entries = append(entries, entry)
+	}
+
+	var newKeys []string
+	templateComments := make(map[string]string)
+	if template != nil {
+		for _, entry := range template.entries {
+			if _, ok := content[entry.key]; ok && !seen[entry.key] {
+				newKeys = append(newKeys, entry.key)
+				templateComments[entry.key] = strings.TrimSpace(entry.leading)
+				seen[entry.key] = true
+			}
+		}
+	}
+	var otherKeys []string
+	for key := range content {
+		if !seen[key] {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	newKeys = append(newKeys, otherKeys...)
+
+	for _, key := range newKeys {
+		leading := ""
+		if len(entries) > 0 {
+			leading = "\n"
+		}
+		if comment := templateComments[key]; comment != "" {
+			if len(entries) > 0 {
+				leading += "\n"
+			}
+			leading += comment + "\n"
+		}
+
+		text := fmt.Sprint(content[key])
+		entries = append(entries, &stringsEntry{
+			leading: leading,
+			raw:     formatStringsPair(key, text),
+			key:     key,
+			value:   text,
+		})
+	}
+
+	if len(entries) > 0 {
+		// The line break ending the previous pair is part of the leading
+		// text, so it is moved along with pairs that change places
+		first := entries[0]
+		reorder(entries, order, func(entry *stringsEntry) (string, bool) {
+			return entry.key, true
+		})
+		if first != entries[0] {
+			first.leading = "\n" + first.leading
+			entries[0].leading = strings.TrimLeft(entries[0].leading, "\n")
+		}
+	}
+
+	d.entries = entries
+}
+
+// bytes renders the document in its encoding
+func (d *stringsDocument) bytes() []byte {
+	var builder strings.Builder
+	for _, entry := range d.entries {
+		builder.WriteString(entry.leading)
+		builder.WriteString(entry.raw)
+	}
+	builder.WriteString(d.trailer)
+	text := builder.String()
+
+	var order binary.ByteOrder
+	switch d.encoding {
+	case "utf-16le":
+		order = binary.LittleEndian
+	case "utf-16be":
+		order = binary.BigEndian
+	default:
+		return []byte(text)
+	}
+
+	units := utf16.Encode([]rune("\ufeff" + text))
+	data := make([]byte, len(units)*2)
+	for i, unit := range units {
+		order.PutUint16(data[i*2:], unit)
+	}
+	return data
+}
+
+// formatStringsPair writes a "key" = "value"; pair
+func formatStringsPair(key, value string) string {
+	return quoteStrings(key) + " = " + quoteStrings(value) + ";"
+}
+
+// quoteStrings quotes a .strings string
+func quoteStrings(value string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\t", `\t`,
+		"\r", `\r`,
+	).Replace(value) + `"`
+}
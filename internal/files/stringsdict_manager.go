@@ -0,0 +1,385 @@
+package files
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// stringsdictFormatKeys are the keys of a .stringsdict plural rule that
+// describe the rule instead of holding text
+var stringsdictFormatKeys = map[string]bool{
+	"NSStringFormatSpecTypeKey":  true,
+	"NSStringFormatValueTypeKey": true,
+}
+
+// StringsdictManager implements FileManager for Apple .stringsdict plural dictionaries.
+//
+// Files live in <lang>.lproj/Localizable.stringsdict. Every entry is a
+// nested object holding its NSStringLocalizedFormatKey and one nested
+// object per variable with its plural forms, which get the CLDR plural
+// categories of every language. The rule type keys are not
+// part of the content; they are kept from the existing file, or copied from
+// the first file read for new entries. Existing entries keep their position
+// unless the content is written with an order of its own.
+type StringsdictManager struct {
+	template *plistNode
+}
+
+// NewStringsdictManager creates a new StringsdictManager
+func NewStringsdictManager() *StringsdictManager {
+	return &StringsdictManager{}
+}
+
+// FilePath returns the Localizable.stringsdict file of the lproj folder of lang
+func (m *StringsdictManager) FilePath(folder, lang string, base bool) string {
+	return lprojPath(folder, lang, "Localizable.stringsdict")
+}
+
+// Write saves the content to a .stringsdict file
+func (m *StringsdictManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a .stringsdict file with its keys in the order of the document
+func (m *StringsdictManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	root, err := readPlist(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		root = &plistNode{kind: "dict"}
+	}
+
+	root.update(doc.Content, m.template, doc.Order)
+
+	buffer := &bytes.Buffer{}
+	buffer.WriteString(xml.Header)
+	buffer.WriteString("<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\">\n")
+	buffer.WriteString("<plist version=\"1.0\">\n")
+	root.write(buffer, "")
+	buffer.WriteString("</plist>\n")
+
+	// Write to file
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from a .stringsdict file
+func (m *StringsdictManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a .stringsdict file along with the order of its keys
+func (m *StringsdictManager) ReadDocument(filePath string) (*Document, error) {
+	root, err := readPlist(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.template == nil {
+		m.template = root
+	}
+
+	return &Document{Content: root.content(), Order: root.order()}, nil
+}
+
+// ShapeContent implements ContentShaper. The plural forms of every variable
+// get one form per CLDR plural category of lang, like few and many for
+// Russian. A zero form of the source is kept, since Apple uses it for zero
+// in every language.
+func (m *StringsdictManager) ShapeContent(filePath, lang string, base LanguageContent) LanguageContent {
+	shaped := make(LanguageContent, len(base))
+	for key, value := range base {
+		if entry, ok := AsContent(value); ok {
+			shaped[key] = map[string]interface{}(shapeApplePlurals(entry, lang))
+		} else {
+			shaped[key] = value
+		}
+	}
+	return shaped
+}
+
+// Exists checks if a file exists
+func (m *StringsdictManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// plistNode is a value of a property list
+type plistNode struct {
+	// kind is "dict", "string" or the element name of any other value
+	kind string
+	// keys and children hold the entries of a dict in file order
+	keys     []string
+	children []*plistNode
+	// text is the value of a string, or the raw inner XML of other values
+	text string
+}
+
+// readPlist parses the root dict of a property list file
+func readPlist(filePath string) (*plistNode, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse property list %s: %w", filePath, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local == "plist" {
+			continue
+		}
+
+		root, err := decodePlistValue(decoder, start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse property list %s: %w", filePath, err)
+		}
+		if root.kind != "dict" {
+			return nil, fmt.Errorf("property list %s does not hold a dictionary", filePath)
+		}
+		return root, nil
+	}
+}
+
+// decodePlistValue decodes the value started by start
+func decodePlistValue(decoder *xml.Decoder, start xml.StartElement) (*plistNode, error) {
+	node := &plistNode{kind: start.Name.Local}
+
+	switch node.kind {
+	case "dict":
+		key := ""
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := decoder.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				child, err := decodePlistValue(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key)
+				node.children = append(node.children, child)
+			case xml.EndElement:
+				return node, nil
+			}
+		}
+
+	case "string":
+		if err := decoder.DecodeElement(&node.text, &start); err != nil {
+			return nil, err
+		}
+
+	default:
+		var raw struct {
+			Inner string `xml:",innerxml"`
+		}
+		if err := decoder.DecodeElement(&raw, &start); err != nil {
+			return nil, err
+		}
+		node.text = raw.Inner
+	}
+
+	return node, nil
+}
+
+// get returns the child of a dict stored under key
+func (n *plistNode) get(key string) *plistNode {
+	if n == nil {
+		return nil
+	}
+	for i, childKey := range n.keys {
+		if childKey == key {
+			return n.children[i]
+		}
+	}
+	return nil
+}
+
+// content converts the strings of a dict into LanguageContent
+func (n *plistNode) content() LanguageContent {
+	content := make(LanguageContent)
+	for i, key := range n.keys {
+		switch child := n.children[i]; child.kind {
+		case "string":
+			if !stringsdictFormatKeys[key] {
+				content[key] = child.text
+			}
+		case "dict":
+			content[key] = map[string]interface{}(child.content())
+		}
+	}
+	return content
+}
+
+// update makes a dict hold content. Rule type keys and values other than
+// strings and dicts are kept, removed keys are dropped and new keys are
+// appended in the order of the template, followed by the others in sorted
+// order. With an order, keys are then moved to their place in it.
+func (n *plistNode) update(content LanguageContent, template *plistNode, order *KeyOrder) {
+	seen := make(map[string]bool)
+	var keys []string
+	var children []*plistNode
+
+	for i, key := range n.keys {
+		child := n.children[i]
+		if stringsdictFormatKeys[key] || child.kind != "string" && child.kind != "dict" {
+			keys = append(keys, key)
+			children = append(children, child)
+			continue
+		}
+
+		value, ok := content[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		keys = append(keys, key)
+		children = append(children, newPlistNode(child, value, template.get(key), order.Child(key)))
+	}
+
+	var newKeys []string
+	if template != nil {
+		for _, key := range template.keys {
+			if _, ok := content[key]; ok && !seen[key] {
+				newKeys = append(newKeys, key)
+				seen[key] = true
+			}
+		}
+	}
+	var otherKeys []string
+	for key := range content {
+		// Descriptions of plural forms are not part of the dictionary
+		if !seen[key] && !IsMetadataKey(key) {
+			otherKeys = append(otherKeys, key)
+		}
+	}
+	sort.Strings(otherKeys)
+	newKeys = append(newKeys, otherKeys...)
+
+	for _, key := range newKeys {
+		keys = append(keys, key)
+		children = append(children, newPlistNode(nil, content[key], template.get(key), order.Child(key)))
+	}
+
+	indexes := make([]int, len(keys))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	reorder(indexes, order, func(i int) (string, bool) {
+		return keys[i], !stringsdictFormatKeys[keys[i]] && (children[i].kind == "string" || children[i].kind == "dict")
+	})
+
+	n.keys = make([]string, len(indexes))
+	n.children = make([]*plistNode, len(indexes))
+	for i, index := range indexes {
+		n.keys[i] = keys[index]
+		n.children[i] = children[index]
+	}
+}
+
+// order returns the order of the keys of the content of a dict
+func (n *plistNode) order() *KeyOrder {
+	order := &KeyOrder{}
+	for i, key := range n.keys {
+		switch child := n.children[i]; child.kind {
+		case "string":
+			if !stringsdictFormatKeys[key] {
+				order.Add(key, nil)
+			}
+		case "dict":
+			order.Add(key, child.order())
+		}
+	}
+	return order
+}
+
+// newPlistNode returns the node holding value, updating existing in place when both are dicts
+func newPlistNode(existing *plistNode, value interface{}, template *plistNode, order *KeyOrder) *plistNode {
+	nested, ok := AsContent(value)
+	if !ok {
+		return &plistNode{kind: "string", text: fmt.Sprint(value)}
+	}
+
+	if existing == nil || existing.kind != "dict" {
+		existing = &plistNode{kind: "dict"}
+		// Copy the rule type of new entries from the template
+		if template != nil {
+			for i, key := range template.keys {
+				if stringsdictFormatKeys[key] {
+					existing.keys = append(existing.keys, key)
+					existing.children = append(existing.children, template.children[i])
+				}
+			}
+		}
+	}
+	existing.update(nested, template, order)
+	return existing
+}
+
+// write renders a value indented with tabs like Xcode does
+func (n *plistNode) write(buffer *bytes.Buffer, indent string) {
+	switch n.kind {
+	case "dict":
+		buffer.WriteString(indent + "<dict>\n")
+		for i, key := range n.keys {
+			buffer.WriteString(indent + "\t<key>")
+			xml.EscapeText(buffer, []byte(key))
+			buffer.WriteString("</key>\n")
+			n.children[i].write(buffer, indent+"\t")
+		}
+		buffer.WriteString(indent + "</dict>\n")
+
+	case "string":
+		buffer.WriteString(indent + "<string>")
+		xml.EscapeText(buffer, []byte(n.text))
+		buffer.WriteString("</string>\n")
+
+	default:
+		if strings.TrimSpace(n.text) == "" {
+			buffer.WriteString(indent + "<" + n.kind + "/>\n")
+		} else {
+			buffer.WriteString(indent + "<" + n.kind + ">" + n.text + "</" + n.kind + ">\n")
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package files_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+const testAndroidStrings = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name" translatable="false">Globify</string>
+    <!-- Greeting on the home screen -->
+    <string name="greeting">Hello, %1$s! Don\'t forget &amp; smile</string>
+    <string name="styled">Tap <b>here</b></string>
+    <plurals name="songs">
+        <item quantity="one">%d song</item>
+        <item quantity="other">%d songs</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Mercury</item>
+        <item>Venus</item>
+    </string-array>
+</resources>
+`
+
+func TestAndroidManagerRead(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewAndroidManager()
+
+	basePath := manager.FilePath(tempDir, "en", true)
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		t.Fatalf("Failed to create values dir: %v", err)
+	}
+	if err := os.WriteFile(basePath, []byte(testAndroidStrings), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := files.LanguageContent{
+		"greeting": "Hello, %1$s! Don't forget & smile",
+		"styled":   "Tap <b>here</b>",
+		"songs": map[string]interface{}{
+			"one":   "%d song",
+			"other": "%d songs",
+		},
+		"planets": map[string]interface{}{
+			"0": "Mercury",
+			"1": "Venus",
+		},
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %#v, want %#v", content, want)
+	}
+
+	// Invalid content
+	invalidFilePath := filepath.Join(tempDir, "invalid.xml")
+	if err := os.WriteFile(invalidFilePath, []byte("<resources><string name=\"a\">"), 0644); err != nil {
+		t.Fatalf("Failed to write invalid file: %v", err)
+	}
+	if _, err := manager.Read(invalidFilePath); err == nil {
+		t.Errorf("Read() with invalid content should return error")
+	}
+}
+
+func TestAndroidManagerWrite(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewAndroidManager()
+
+	basePath := manager.FilePath(tempDir, "en", true)
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		t.Fatalf("Failed to create values dir: %v", err)
+	}
+	if err := os.WriteFile(basePath, []byte(testAndroidStrings), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+	if _, err := manager.Read(basePath); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	targetPath := manager.FilePath(tempDir, "pt-BR", false)
+	if want := filepath.Join(tempDir, "values-pt-rBR", "strings.xml"); targetPath != want {
+		t.Errorf("FilePath() = %s, want %s", targetPath, want)
+	}
+
+	err := manager.Write(targetPath, files.LanguageContent{
+		"greeting": "Olá, %1$s! Não se esqueça & sorria",
+		"styled":   "Toque <b>aqui</b>",
+		"songs": map[string]interface{}{
+			"one":   "%d música",
+			"other": "%d músicas",
+		},
+		"planets": map[string]interface{}{
+			"0": "Mercúrio",
+			"1": "Vênus",
+		},
+		"extra": "@mention's",
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+
+	want := `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="greeting">Olá, %1$s! Não se esqueça &amp; sorria</string>
+    <string name="styled">Toque <b>aqui</b></string>
+    <plurals name="songs">
+        <item quantity="one">%d música</item>
+        <item quantity="other">%d músicas</item>
+    </plurals>
+    <string-array name="planets">
+        <item>Mercúrio</item>
+        <item>Vênus</item>
+    </string-array>
+    <string name="extra">\@mention\'s</string>
+</resources>
+`
+	if string(data) != want {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Updating the file keeps comments and untranslatable resources
+	if err := manager.Write(basePath, files.LanguageContent{
+		"greeting": "Hi, %1$s!",
+		"styled":   "Tap <b>here</b>",
+	}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err = os.ReadFile(basePath)
+	if err != nil {
+		t.Fatalf("Failed to read base file: %v", err)
+	}
+	got := string(data)
+	for _, expected := range []string{
+		`<string name="app_name" translatable="false">Globify</string>`,
+		"<!-- Greeting on the home screen -->\n    <string name=\"greeting\">Hi, %1$s!</string>",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Write() wrote:\n%s\nwhich does not contain %q", got, expected)
+		}
+	}
+	if strings.Contains(got, "songs") {
+		t.Errorf("Write() kept a removed resource:\n%s", got)
+	}
+}
+
+func TestAndroidQualifier(t *testing.T) {
+	tests := map[string]string{
+		"fr":         "fr",
+		"pt-BR":      "pt-rBR",
+		"zh-Hans":    "b+zh+Hans",
+		"zh-Hant-TW": "b+zh+Hant+TW",
+	}
+	for lang, want := range tests {
+		if got := files.AndroidQualifier(lang); got != want {
+			t.Errorf("AndroidQualifier(%q) = %q, want %q", lang, got, want)
+		}
+	}
+}
+
+func TestAndroidManagerShapeContent(t *testing.T) {
+	manager := files.NewAndroidManager()
+	base := files.LanguageContent{
+		"greeting": "Hello",
+		"songs":    map[string]interface{}{"one": "%d song", "other": "%d songs"},
+		"planets":  map[string]interface{}{"0": "Mercury", "1": "Venus"},
+	}
+
+	// Russian plurals get the few and many quantities, described for translators
+	shaped := manager.ShapeContent("values-ru/strings.xml", "ru", base)
+	songs, _ := files.AsContent(shaped["songs"])
+	for quantity, want := range map[string]string{"one": "%d song", "few": "%d songs", "many": "%d songs", "other": "%d songs"} {
+		if songs[quantity] != want {
+			t.Errorf("ShapeContent() ru %s = %v, want %q", quantity, songs[quantity], want)
+		}
+	}
+	if _, ok := files.AsContent(songs["@few"]); !ok {
+		t.Errorf("ShapeContent() ru few has no description: %v", songs)
+	}
+	if !reflect.DeepEqual(shaped["planets"], base["planets"]) || shaped["greeting"] != "Hello" {
+		t.Errorf("ShapeContent() changed strings or arrays: %v", shaped)
+	}
+
+	// Japanese only uses other
+	shaped = manager.ShapeContent("values-ja/strings.xml", "ja", base)
+	if want := map[string]interface{}{"other": "%d songs"}; !reflect.DeepEqual(shaped["songs"], want) {
+		t.Errorf("ShapeContent() ja songs = %v, want %v", shaped["songs"], want)
+	}
+
+	// Descriptions are not written to the file
+	filePath := filepath.Join(t.TempDir(), "values-ru", "strings.xml")
+	if err := manager.Write(filePath, manager.ShapeContent(filePath, "ru", base)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read written file: %v", err)
+	}
+	if !strings.Contains(string(data), `<item quantity="many">%d songs</item>`) || strings.Contains(string(data), "@few") {
+		t.Errorf("Write() output = %s", data)
+	}
+}
@@ -0,0 +1,394 @@
+package files_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+func TestStringsManager(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewStringsManager()
+
+	basePath := manager.FilePath(tempDir, "en", true)
+	if want := filepath.Join(tempDir, "en.lproj", "Localizable.strings"); basePath != want {
+		t.Errorf("FilePath() = %s, want %s", basePath, want)
+	}
+
+	base := "/* Greeting on the home screen */\n\"greeting\" = \"Hello, %@!\";\n\n// Unquoted key\nfarewell = \"Say \\\"bye\\\"\\n\";\n"
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		t.Fatalf("Failed to create lproj dir: %v", err)
+	}
+	if err := os.WriteFile(basePath, encodeUTF16LE(base), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := files.LanguageContent{
+		"greeting": "Hello, %@!",
+		"farewell": "Say \"bye\"\n",
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %#v, want %#v", content, want)
+	}
+
+	// New files keep the encoding and comments of the base file
+	targetPath := manager.FilePath(tempDir, "fr", false)
+	err = manager.Write(targetPath, files.LanguageContent{
+		"greeting": "Bonjour, %@ !",
+		"farewell": "Dites \"au revoir\"\n",
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	if !reflect.DeepEqual(data[:2], []byte{0xFF, 0xFE}) {
+		t.Errorf("Write() did not keep the UTF-16 encoding")
+	}
+	wantText := "/* Greeting on the home screen */\n\"greeting\" = \"Bonjour, %@ !\";\n\n// Unquoted key\n\"farewell\" = \"Dites \\\"au revoir\\\"\\n\";\n"
+	if got := decodeUTF16LE(data); got != wantText {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", got, wantText)
+	}
+
+	// Updating a file only rewrites the changed pairs
+	if err := manager.Write(basePath, files.LanguageContent{"greeting": "Hi, %@!", "farewell": "Say \"bye\"\n"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err = os.ReadFile(basePath)
+	if err != nil {
+		t.Fatalf("Failed to read base file: %v", err)
+	}
+	if got := decodeUTF16LE(data); !strings.Contains(got, "farewell = \"Say \\\"bye\\\"\\n\";") || !strings.Contains(got, "\"greeting\" = \"Hi, %@!\";") {
+		t.Errorf("Write() wrote:\n%s", got)
+	}
+}
+
+func TestStringsManagerWriteDocumentReorders(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewStringsManager()
+
+	targetPath := manager.FilePath(tempDir, "fr", false)
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	existing := "/* Title */\n\"title\" = \"Titre\";\n\n/* Footer */\n\"footer\" = \"Pied\";\n"
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+
+	order := &files.KeyOrder{}
+	order.Add("footer", nil)
+	order.Add("title", nil)
+	err := manager.WriteDocument(targetPath, &files.Document{
+		Content: files.LanguageContent{"title": "Titre", "footer": "Pied"},
+		Order:   order,
+	})
+	if err != nil {
+		t.Fatalf("WriteDocument() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	want := "/* Footer */\n\"footer\" = \"Pied\";\n/* Title */\n\"title\" = \"Titre\";\n"
+	if string(data) != want {
+		t.Errorf("WriteDocument() wrote:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestStringsdictManager(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewStringsdictManager()
+
+	basePath := manager.FilePath(tempDir, "en", true)
+	base := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>songs</key>
+	<dict>
+		<key>NSStringLocalizedFormatKey</key>
+		<string>%#@songs@</string>
+		<key>songs</key>
+		<dict>
+			<key>NSStringFormatSpecTypeKey</key>
+			<string>NSStringPluralRuleType</string>
+			<key>NSStringFormatValueTypeKey</key>
+			<string>d</string>
+			<key>one</key>
+			<string>%d song</string>
+			<key>other</key>
+			<string>%d songs</string>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		t.Fatalf("Failed to create lproj dir: %v", err)
+	}
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := files.LanguageContent{
+		"songs": map[string]interface{}{
+			"NSStringLocalizedFormatKey": "%#@songs@",
+			"songs": map[string]interface{}{
+				"one":   "%d song",
+				"other": "%d songs",
+			},
+		},
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %#v, want %#v", content, want)
+	}
+
+	// New files copy the rule type from the base file
+	targetPath := manager.FilePath(tempDir, "fr", false)
+	err = manager.Write(targetPath, files.LanguageContent{
+		"songs": map[string]interface{}{
+			"NSStringLocalizedFormatKey": "%#@songs@",
+			"songs": map[string]interface{}{
+				"one":   "%d chanson",
+				"other": "%d chansons",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	wantFile := strings.NewReplacer("%d song<", "%d chanson<", "%d songs<", "%d chansons<").Replace(base)
+	if string(data) != wantFile {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, wantFile)
+	}
+}
+
+func TestXCStringsManager(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewXCStringsManager()
+
+	catalogPath := manager.FilePath(tempDir, "fr", false)
+	if catalogPath != manager.FilePath(tempDir, "en", true) {
+		t.Errorf("FilePath() differs between languages")
+	}
+
+	catalog := `{
+  "sourceLanguage" : "en",
+  "strings" : {
+    "Hello" : {
+      "comment" : "Greeting",
+      "localizations" : {
+        "de" : {
+          "stringUnit" : {
+            "state" : "translated",
+            "value" : "Hallo"
+          }
+        }
+      }
+    },
+    "%lld items" : {
+      "localizations" : {
+        "en" : {
+          "variations" : {
+            "plural" : {
+              "one" : {
+                "stringUnit" : {
+                  "state" : "translated",
+                  "value" : "%lld item"
+                }
+              },
+              "other" : {
+                "stringUnit" : {
+                  "state" : "translated",
+                  "value" : "%lld items"
+                }
+              }
+            }
+          }
+        }
+      }
+    },
+    "MyApp" : {
+      "shouldTranslate" : false
+    }
+  },
+  "version" : "1.0"
+}`
+	if err := os.WriteFile(catalogPath, []byte(catalog), 0644); err != nil {
+		t.Fatalf("Failed to write catalog: %v", err)
+	}
+
+	content, err := manager.Read(catalogPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := files.LanguageContent{
+		"Hello": "Hello",
+		"%lld items": map[string]interface{}{
+			"one":   "%lld item",
+			"other": "%lld items",
+		},
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %#v, want %#v", content, want)
+	}
+
+	fr, err := manager.ReadLanguage(catalogPath, "fr")
+	if err != nil || len(fr) != 0 {
+		t.Errorf("ReadLanguage() = %v, %v, want empty content", fr, err)
+	}
+
+	err = manager.WriteLanguage(catalogPath, "fr", files.LanguageContent{
+		"Hello": "Bonjour",
+		"%lld items": map[string]interface{}{
+			"one":   "%lld élément",
+			"other": "%lld éléments",
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteLanguage() error = %v", err)
+	}
+
+	fr, err = manager.ReadLanguage(catalogPath, "fr")
+	if err != nil {
+		t.Fatalf("ReadLanguage() error = %v", err)
+	}
+	wantFr := files.LanguageContent{
+		"Hello": "Bonjour",
+		"%lld items": map[string]interface{}{
+			"one":   "%lld élément",
+			"other": "%lld éléments",
+		},
+	}
+	if !reflect.DeepEqual(fr, wantFr) {
+		t.Errorf("ReadLanguage() got = %#v, want %#v", fr, wantFr)
+	}
+
+	// Other languages and untranslated metadata are kept
+	de, err := manager.ReadLanguage(catalogPath, "de")
+	if err != nil || !reflect.DeepEqual(de, files.LanguageContent{"Hello": "Hallo"}) {
+		t.Errorf("ReadLanguage() = %v, %v, want the German translation", de, err)
+	}
+	data, err := os.ReadFile(catalogPath)
+	if err != nil {
+		t.Fatalf("Failed to read catalog: %v", err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatalf("Catalog is not valid JSON: %v", err)
+	}
+	if !strings.Contains(string(data), `"comment" : "Greeting"`) || !strings.Contains(string(data), `"shouldTranslate" : false`) {
+		t.Errorf("WriteLanguage() lost metadata:\n%s", data)
+	}
+}
+
+func TestStringsdictManagerShapeContent(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewStringsdictManager()
+	base := files.LanguageContent{
+		"songs": map[string]interface{}{
+			"NSStringLocalizedFormatKey": "%#@count@",
+			"count":                      map[string]interface{}{"zero": "No songs", "one": "%d song", "other": "%d songs"},
+		},
+	}
+
+	// Russian gets few and many, and keeps the explicit zero
+	shaped := manager.ShapeContent(manager.FilePath(tempDir, "ru", false), "ru", base)
+	entry, _ := files.AsContent(shaped["songs"])
+	count, _ := files.AsContent(entry["count"])
+	for category, want := range map[string]string{"zero": "No songs", "one": "%d song", "few": "%d songs", "many": "%d songs", "other": "%d songs"} {
+		if count[category] != want {
+			t.Errorf("ShapeContent() ru %s = %v, want %q", category, count[category], want)
+		}
+	}
+	if entry["NSStringLocalizedFormatKey"] != "%#@count@" {
+		t.Errorf("ShapeContent() changed the format key: %v", entry)
+	}
+
+	// Descriptions of the new forms are not written
+	filePath := manager.FilePath(tempDir, "ru", false)
+	if err := manager.Write(filePath, shaped); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	content, err := manager.Read(filePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	written, _ := files.AsContent(content["songs"])
+	if got, _ := files.AsContent(written["count"]); len(got) != 5 {
+		t.Errorf("Read() count forms = %v, want 5 forms", got)
+	}
+}
+
+func TestXCStringsManagerShapeContent(t *testing.T) {
+	manager := files.NewXCStringsManager()
+	base := files.LanguageContent{
+		"Hello":    "Hello",
+		"%d songs": map[string]interface{}{"one": "%d song", "other": "%d songs"},
+	}
+
+	shaped := manager.ShapeContent("Localizable.xcstrings", "ja", base)
+	if want := map[string]interface{}{"other": "%d songs"}; !reflect.DeepEqual(shaped["%d songs"], want) {
+		t.Errorf("ShapeContent() ja = %v, want %v", shaped["%d songs"], want)
+	}
+
+	// Polish plural variations are written with all four categories
+	filePath := filepath.Join(t.TempDir(), "Localizable.xcstrings")
+	if err := manager.Write(filePath, base); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := manager.WriteLanguage(filePath, "pl", manager.ShapeContent(filePath, "pl", base)); err != nil {
+		t.Fatalf("WriteLanguage() error = %v", err)
+	}
+	content, err := manager.ReadLanguage(filePath, "pl")
+	if err != nil {
+		t.Fatalf("ReadLanguage() error = %v", err)
+	}
+	want := map[string]interface{}{"one": "%d song", "few": "%d songs", "many": "%d songs", "other": "%d songs"}
+	if !reflect.DeepEqual(content["%d songs"], want) {
+		t.Errorf("ReadLanguage() pl = %v, want %v", content["%d songs"], want)
+	}
+}
+
+// encodeUTF16LE encodes text as UTF-16 little endian with a byte order mark
+func encodeUTF16LE(text string) []byte {
+	units := utf16.Encode([]rune(text))
+	data := []byte{0xFF, 0xFE}
+	for _, unit := range units {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+	return data
+}
+
+// decodeUTF16LE decodes UTF-16 little endian text with a byte order mark
+func decodeUTF16LE(data []byte) string {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 2; i+1 < len(data); i += 2 {
+		units = append(units, uint16(data[i])|uint16(data[i+1])<<8)
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,123 @@
+package files_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+func TestARBManager(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewARBManager()
+
+	basePath := manager.FilePath(tempDir, "en", true)
+	if want := filepath.Join(tempDir, "app_en.arb"); basePath != want {
+		t.Errorf("FilePath() = %s, want %s", basePath, want)
+	}
+	targetPath := manager.FilePath(tempDir, "pt-BR", false)
+	if want := filepath.Join(tempDir, "app_pt_BR.arb"); targetPath != want {
+		t.Errorf("FilePath() = %s, want %s", targetPath, want)
+	}
+
+	base := `{
+  "@@locale": "en",
+  "title": "My <b>app</b>",
+  "@title": {
+    "description": "Title of the app"
+  },
+  "greeting": "Hello {name}",
+  "@greeting": {
+    "placeholders": {
+      "name": {
+        "type": "String"
+      }
+    }
+  }
+}
+`
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if content["@@locale"] != "en" || content["greeting"] != "Hello {name}" {
+		t.Errorf("Read() got = %#v", content)
+	}
+
+	// Targets declare their own locale and follow the order of the base file
+	translated := make(files.LanguageContent)
+	for key, value := range content {
+		translated[key] = value
+	}
+	translated["title"] = "Meu <b>app</b>"
+	translated["greeting"] = "Olá {name}"
+	if err := manager.Write(targetPath, translated); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	want := `{
+  "@@locale": "pt_BR",
+  "title": "Meu <b>app</b>",
+  "@title": {
+    "description": "Title of the app"
+  },
+  "greeting": "Olá {name}",
+  "@greeting": {
+    "placeholders": {
+      "name": {
+        "type": "String"
+      }
+    }
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	// The content passed to Write is not modified
+	if translated["@@locale"] != "en" {
+		t.Errorf("Write() modified its content")
+	}
+
+	// Keys unknown to the base file are sorted, with metadata after its message
+	otherPath := manager.FilePath(tempDir, "de", false)
+	err = manager.Write(otherPath, files.LanguageContent{
+		"@zeta": map[string]interface{}{"description": "Last"},
+		"zeta":  "Zeta",
+		"alpha": "Alpha",
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err = os.ReadFile(otherPath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	want = "{\n  \"alpha\": \"Alpha\",\n  \"zeta\": \"Zeta\",\n  \"@zeta\": {\n    \"description\": \"Last\"\n  }\n}\n"
+	if string(data) != want {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	reread, err := manager.Read(otherPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(reread, files.LanguageContent{
+		"@zeta": map[string]interface{}{"description": "Last"},
+		"zeta":  "Zeta",
+		"alpha": "Alpha",
+	}) {
+		t.Errorf("Read() got = %#v", reread)
+	}
+}
@@ -115,8 +115,48 @@ func TestNewFileManager(t *testing.T) {
 			wantErr:  false,
 		},
 		{
-			name:     "Unsupported file type",
+			name:     "YAML manager",
 			fileType: "yaml",
+			wantErr:  false,
+		},
+		{
+			name:     "YML manager",
+			fileType: "yml",
+			wantErr:  false,
+		},
+		{
+			name:     "PO manager",
+			fileType: "po",
+			wantErr:  false,
+		},
+		{
+			name:     "Android manager",
+			fileType: "xml",
+			wantErr:  false,
+		},
+		{
+			name:     "Strings manager",
+			fileType: "strings",
+			wantErr:  false,
+		},
+		{
+			name:     "Stringsdict manager",
+			fileType: "stringsdict",
+			wantErr:  false,
+		},
+		{
+			name:     "String Catalog manager",
+			fileType: "xcstrings",
+			wantErr:  false,
+		},
+		{
+			name:     "ARB manager",
+			fileType: "arb",
+			wantErr:  false,
+		},
+		{
+			name:     "Unsupported file type",
+			fileType: "txt",
 			wantErr:  true,
 		},
 	}
@@ -129,4 +169,110 @@ func TestNewFileManager(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestJSONManagerDocumentOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewJSONManager()
+
+	basePath := filepath.Join(tempDir, "en.json")
+	base := `{
+  "zebra": "Zebra",
+  "nested": {
+    "b": "B",
+    "a": "A"
+  },
+  "apple": "Apple"
+}
+`
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	doc, err := manager.ReadDocument(basePath)
+	if err != nil {
+		t.Fatalf("ReadDocument() error = %v", err)
+	}
+	if want := []string{"zebra", "nested", "apple"}; !reflect.DeepEqual(doc.Order.Keys, want) {
+		t.Errorf("ReadDocument() order = %v, want %v", doc.Order.Keys, want)
+	}
+
+	// Keys follow the order of the document, unknown keys come last in sorted order
+	targetPath := filepath.Join(tempDir, "fr.json")
+	err = manager.WriteDocument(targetPath, &files.Document{
+		Content: files.LanguageContent{
+			"apple":  "Pomme",
+			"zebra":  "Zèbre",
+			"extra":  "Extra",
+			"nested": map[string]interface{}{"a": "A", "b": "B"},
+		},
+		Order: doc.Order,
+	})
+	if err != nil {
+		t.Fatalf("WriteDocument() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	want := `{
+  "zebra": "Zèbre",
+  "nested": {
+    "b": "B",
+    "a": "A"
+  },
+  "apple": "Pomme",
+  "extra": "Extra"
+}
+`
+	if string(data) != want {
+		t.Errorf("WriteDocument() wrote:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestJSONManagerArrayObjectOrder(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewJSONManager()
+
+	base := `{
+  "z": "1",
+  "steps": [
+    {
+      "title": "a",
+      "body": "b"
+    },
+    [
+      {
+        "y": "c",
+        "x": "d"
+      }
+    ],
+    "e"
+  ],
+  "empty": [],
+  "a": "2"
+}
+`
+	basePath := filepath.Join(tempDir, "en.json")
+	if err := os.WriteFile(basePath, []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+	doc, err := manager.ReadDocument(basePath)
+	if err != nil {
+		t.Fatalf("ReadDocument() error = %v", err)
+	}
+
+	// Objects inside arrays, nested arrays included, keep the order of the base file
+	targetPath := filepath.Join(tempDir, "fr.json")
+	if err := manager.WriteDocument(targetPath, doc); err != nil {
+		t.Fatalf("WriteDocument() error = %v", err)
+	}
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	if string(data) != base {
+		t.Errorf("WriteDocument() wrote:\n%s\nwant:\n%s", data, base)
+	}
+}
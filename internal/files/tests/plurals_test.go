@@ -0,0 +1,24 @@
+package files_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+func TestPluralCategories(t *testing.T) {
+	tests := map[string][]string{
+		"en":    {"one", "other"},
+		"ru":    {"one", "few", "many", "other"},
+		"pl-PL": {"one", "few", "many", "other"},
+		"ar":    {"zero", "one", "two", "few", "many", "other"},
+		"zh_CN": {"other"},
+		"fr":    {"one", "many", "other"},
+	}
+	for lang, want := range tests {
+		if got := files.PluralCategories(lang); !reflect.DeepEqual(got, want) {
+			t.Errorf("PluralCategories(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package files_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+const testPOTemplate = `# Application messages
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#. Shown on the home page
+#: src/home.go:12
+msgid "Hello"
+msgstr ""
+
+#: src/menu.go:4
+msgctxt "menu"
+msgid "Open"
+msgstr ""
+
+#: src/files.go:20
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+msgid ""
+"First line\n"
+"Second line"
+msgstr ""
+`
+
+func TestPOManagerRead(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewPOManager()
+
+	basePath := filepath.Join(tempDir, "en.po")
+	if err := os.WriteFile(basePath, []byte(testPOTemplate), 0644); err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := files.LanguageContent{
+		"Hello":                                  "Hello",
+		"menu" + files.ContextSeparator + "Open": "Open",
+		"%d file": map[string]interface{}{
+			"0": "%d file",
+			"1": "%d files",
+		},
+		"First line\nSecond line": "First line\nSecond line",
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %#v, want %#v", content, want)
+	}
+
+	// Invalid content
+	invalidFilePath := filepath.Join(tempDir, "invalid.po")
+	if err := os.WriteFile(invalidFilePath, []byte("msgid \"unclosed\nmsgstr \"\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write invalid file: %v", err)
+	}
+	if _, err := manager.Read(invalidFilePath); err == nil {
+		t.Errorf("Read() with invalid content should return error")
+	}
+
+	// Non-existent file
+	if _, err := manager.Read(filepath.Join(tempDir, "nonexistent.po")); err == nil {
+		t.Errorf("Read() with non-existent file should return error")
+	}
+}
+
+func TestPOManagerWriteFromTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewPOManager()
+
+	if err := os.WriteFile(filepath.Join(tempDir, "messages.pot"), []byte(testPOTemplate), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	targetPath := filepath.Join(tempDir, "pl.po")
+	err := manager.Write(targetPath, files.LanguageContent{
+		"Hello":                                  "Cześć",
+		"menu" + files.ContextSeparator + "Open": "Otwórz",
+		"%d file": map[string]interface{}{
+			"0": "%d plik",
+			"1": "%d pliki",
+			"2": "%d plików",
+		},
+		"First line\nSecond line": "Pierwsza linia\nDruga linia",
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+
+	want := `# Application messages
+msgid ""
+msgstr ""
+"Project-Id-Version: app 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+#. Shown on the home page
+#: src/home.go:12
+msgid "Hello"
+msgstr "Cześć"
+
+#: src/menu.go:4
+msgctxt "menu"
+msgid "Open"
+msgstr "Otwórz"
+
+#: src/files.go:20
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d plik"
+msgstr[1] "%d pliki"
+msgstr[2] "%d plików"
+
+msgid ""
+"First line\n"
+"Second line"
+msgstr ""
+"Pierwsza linia\n"
+"Druga linia"
+`
+	if string(data) != want {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	// The plural entry reads back with every form of the language
+	content, err := manager.Read(targetPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	wantPlural := map[string]interface{}{"0": "%d plik", "1": "%d pliki", "2": "%d plików"}
+	if !reflect.DeepEqual(content["%d file"], wantPlural) {
+		t.Errorf("Read() plural got = %v, want %v", content["%d file"], wantPlural)
+	}
+}
+
+func TestPOManagerUpdatesExistingFile(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewPOManager()
+
+	targetPath := filepath.Join(tempDir, "pl.po")
+	existing := `msgid ""
+msgstr ""
+"Language: pl\n"
+"Plural-Forms: nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+# Reviewed by the agency
+#, fuzzy
+msgid "Hello"
+msgstr "Witaj"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d plik"
+msgstr[1] "%d pliki"
+msgstr[2] "%d plików"
+
+msgid "Removed"
+msgstr "Usunięty"
+`
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+
+	// Fuzzy translations read as untranslated
+	content, err := manager.Read(targetPath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if content["Hello"] != "Hello" {
+		t.Errorf("Read() fuzzy message got = %v, want its msgid", content["Hello"])
+	}
+
+	err = manager.Write(targetPath, files.LanguageContent{
+		"Hello": "Cześć",
+		"%d file": map[string]interface{}{
+			"0": "%d plik",
+			"1": "%d pliki",
+		},
+		"Added": "Dodany",
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	got := string(data)
+
+	if !strings.Contains(got, "# Reviewed by the agency\nmsgid \"Hello\"\nmsgstr \"Cześć\"\n") {
+		t.Errorf("Write() did not keep the comment or clear the fuzzy flag:\n%s", got)
+	}
+	if !strings.Contains(got, "msgstr[2] \"%d plików\"\n") {
+		t.Errorf("Write() did not keep the extra plural form:\n%s", got)
+	}
+	if strings.Contains(got, "Removed") {
+		t.Errorf("Write() kept a removed message:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "msgid \"Added\"\nmsgstr \"Dodany\"\n") {
+		t.Errorf("Write() did not append the new message:\n%s", got)
+	}
+}
+
+func TestPOManagerShapeContent(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewPOManager()
+
+	base := files.LanguageContent{
+		"Hello": "Hello",
+		"%d file": map[string]interface{}{
+			"0": "%d file",
+			"1": "%d files",
+		},
+	}
+
+	// Russian has three forms, the extra one described with the rule
+	shaped := manager.ShapeContent(filepath.Join(tempDir, "ru.po"), "ru", base)
+	if shaped["Hello"] != "Hello" {
+		t.Errorf("ShapeContent() changed a singular message: %v", shaped["Hello"])
+	}
+	plural, _ := files.AsContent(shaped["%d file"])
+	if plural["0"] != "%d file" || plural["1"] != "%d files" || plural["2"] != "%d files" {
+		t.Errorf("ShapeContent() ru forms = %v", plural)
+	}
+	metadata, _ := files.AsContent(plural["@2"])
+	if description, _ := metadata["description"].(string); !strings.Contains(description, files.PluralForms("ru")) {
+		t.Errorf("ShapeContent() ru form 2 description = %q", description)
+	}
+
+	// Chinese has a single form, translated from the plural source
+	shaped = manager.ShapeContent(filepath.Join(tempDir, "zh_CN.po"), "zh_CN", base)
+	want := map[string]interface{}{"0": "%d files"}
+	if !reflect.DeepEqual(shaped["%d file"], want) {
+		t.Errorf("ShapeContent() zh_CN forms = %v, want %v", shaped["%d file"], want)
+	}
+
+	// Existing files keep the forms of their own header
+	existing := "msgid \"\"\nmsgstr \"\"\n\"Language: ru\\n\"\n\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n"
+	targetPath := filepath.Join(tempDir, "ru-custom.po")
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+	plural, _ = files.AsContent(manager.ShapeContent(targetPath, "ru", base)["%d file"])
+	if len(plural) != 2 {
+		t.Errorf("ShapeContent() with the header of the file = %v, want 2 forms", plural)
+	}
+
+	// Forms missing from the content are left empty instead of copying another form
+	if err := manager.Write(filepath.Join(tempDir, "ru.po"), files.LanguageContent{
+		"%d file": map[string]interface{}{"0": "%d файл", "1": "%d файла"},
+	}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "ru.po"))
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	if !strings.Contains(string(data), "msgstr[1] \"%d файла\"\nmsgstr[2] \"\"\n") {
+		t.Errorf("Write() filled a missing plural form:\n%s", data)
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	tests := map[string]string{
+		"en":      "nplurals=2; plural=(n != 1);",
+		"fr":      "nplurals=2; plural=(n > 1);",
+		"ja":      "nplurals=1; plural=0;",
+		"zh-Hans": "nplurals=1; plural=0;",
+		"zh_CN":   "nplurals=1; plural=0;",
+		"sr_RS":   files.PluralForms("sr"),
+	}
+	for lang, want := range tests {
+		if got := files.PluralForms(lang); got != want {
+			t.Errorf("PluralForms(%q) = %q, want %q", lang, got, want)
+		}
+	}
+}
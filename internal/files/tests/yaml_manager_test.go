@@ -0,0 +1,203 @@
+package files_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+func TestYAMLManager(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewYAMLManager()
+
+	testContent := files.LanguageContent{
+		"greeting": "Hello",
+		"farewell": "Goodbye: see you",
+		"nested": map[string]interface{}{
+			"key1": "Nested value 1",
+			"key2": "Nested value 2",
+		},
+		"count": 3,
+	}
+
+	testFilePath := filepath.Join(tempDir, "nested", "en.yaml")
+
+	exists, err := manager.Exists(testFilePath)
+	if err != nil || exists {
+		t.Errorf("Exists() = %v, %v, want false, nil", exists, err)
+	}
+
+	if err := manager.Write(testFilePath, testContent); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	exists, err = manager.Exists(testFilePath)
+	if err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true, nil", exists, err)
+	}
+
+	readContent, err := manager.Read(testFilePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(readContent, testContent) {
+		t.Errorf("Read() got = %v, want %v", readContent, testContent)
+	}
+
+	// Invalid content
+	invalidFilePath := filepath.Join(tempDir, "invalid.yaml")
+	if err := os.WriteFile(invalidFilePath, []byte("key: [unclosed"), 0644); err != nil {
+		t.Fatalf("Failed to write invalid file: %v", err)
+	}
+	if _, err := manager.Read(invalidFilePath); err == nil {
+		t.Errorf("Read() with invalid content should return error")
+	}
+
+	// Non-existent file
+	if _, err := manager.Read(filepath.Join(tempDir, "nonexistent.yaml")); err == nil {
+		t.Errorf("Read() with non-existent file should return error")
+	}
+}
+
+func TestYAMLManagerLanguageRoot(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewYAMLManager()
+
+	basePath := filepath.Join(tempDir, "en.yml")
+	err := os.WriteFile(basePath, []byte("en:\n  greeting: Hello\n  nested:\n    key1: Nested value\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write base file: %v", err)
+	}
+
+	content, err := manager.Read(basePath)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := files.LanguageContent{
+		"greeting": "Hello",
+		"nested": map[string]interface{}{
+			"key1": "Nested value",
+		},
+	}
+	if !reflect.DeepEqual(content, want) {
+		t.Errorf("Read() got = %v, want %v", content, want)
+	}
+
+	// New files follow the layout of the files read before
+	targetPath := filepath.Join(tempDir, "fr.yml")
+	if err := manager.Write(targetPath, files.LanguageContent{"greeting": "Bonjour"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	if string(data) != "fr:\n  greeting: Bonjour\n" {
+		t.Errorf("Write() wrote %q", string(data))
+	}
+}
+
+func TestYAMLManagerPreservesOrderAndComments(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewYAMLManager()
+
+	targetPath := filepath.Join(tempDir, "fr.yaml")
+	existing := `# Application strings
+zebra: Zèbre # keep me
+apple: Pomme
+removed: Supprimé
+nested:
+  # Nested comment
+  b: B
+  a: A
+`
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+
+	err := manager.Write(targetPath, files.LanguageContent{
+		"zebra": "Zèbre rayé",
+		"apple": "Pomme",
+		"added": "Ajouté",
+		"nested": files.LanguageContent{
+			"a": "A2",
+			"b": "B",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+
+	want := `# Application strings
+zebra: Zèbre rayé # keep me
+apple: Pomme
+nested:
+  # Nested comment
+  b: B
+  a: A2
+added: Ajouté
+`
+	if string(data) != want {
+		t.Errorf("Write() wrote:\n%s\nwant:\n%s", data, want)
+	}
+	if strings.Contains(string(data), "removed") {
+		t.Errorf("Write() kept a removed key")
+	}
+}
+
+func TestYAMLManagerWriteDocumentReorders(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := files.NewYAMLManager()
+
+	targetPath := filepath.Join(tempDir, "fr.yaml")
+	existing := `# Application strings
+
+# Fruit
+apple: Pomme
+zebra: Zèbre # keep me
+`
+	if err := os.WriteFile(targetPath, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to write target file: %v", err)
+	}
+
+	order := &files.KeyOrder{}
+	order.Add("zebra", nil)
+	order.Add("added", nil)
+	order.Add("apple", nil)
+	err := manager.WriteDocument(targetPath, &files.Document{
+		Content: files.LanguageContent{
+			"apple": "Pomme",
+			"zebra": "Zèbre",
+			"added": "Ajouté",
+		},
+		Order: order,
+	})
+	if err != nil {
+		t.Fatalf("WriteDocument() error = %v", err)
+	}
+
+	data, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("Failed to read target file: %v", err)
+	}
+	// Comments of a key move along with it
+	want := `# Application strings
+
+zebra: Zèbre # keep me
+added: Ajouté
+# Fruit
+apple: Pomme
+`
+	if string(data) != want {
+		t.Errorf("WriteDocument() wrote:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,351 @@
+package files
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// XCStringsManager implements MultiLanguageManager for Apple String Catalogs.
+//
+// A single Localizable.xcstrings file holds every language. Every string
+// of the catalog is a key; strings with plural variations are nested
+// objects keyed by plural category, and every language gets the categories
+// of its CLDR plural rules. Strings marked shouldTranslate false
+// are skipped and the source language reads the key itself when it has no
+// localization of its own, like Xcode does. Everything globify does not
+// translate, like comments and extraction states, is kept as it is.
+type XCStringsManager struct{}
+
+// NewXCStringsManager creates a new XCStringsManager
+func NewXCStringsManager() *XCStringsManager {
+	return &XCStringsManager{}
+}
+
+// FilePath returns the catalog, which is the same file for every language
+func (m *XCStringsManager) FilePath(folder, lang string, base bool) string {
+	return filepath.Join(folder, "Localizable.xcstrings")
+}
+
+// Write saves the content of the source language of the catalog. New
+// catalogs are written in English, the default source language of Xcode.
+func (m *XCStringsManager) Write(filePath string, content LanguageContent) error {
+	catalog, err := readCatalog(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		return m.WriteLanguage(filePath, "en", content)
+	}
+	return m.WriteLanguage(filePath, catalog.sourceLanguage(), content)
+}
+
+// Read loads the content of the source language of the catalog
+func (m *XCStringsManager) Read(filePath string) (LanguageContent, error) {
+	catalog, err := readCatalog(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.content(catalog.sourceLanguage()), nil
+}
+
+// WriteDocument saves the content of the document like Write. Catalogs
+// keep their keys sorted like Xcode does, so the order of the document is
+// not used.
+func (m *XCStringsManager) WriteDocument(filePath string, doc *Document) error {
+	return m.Write(filePath, doc.Content)
+}
+
+// ReadDocument loads the content of the source language of the catalog,
+// whose keys are sorted
+func (m *XCStringsManager) ReadDocument(filePath string) (*Document, error) {
+	content, err := m.Read(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Content: content}, nil
+}
+
+// ReadLanguage loads the content of lang from the catalog
+func (m *XCStringsManager) ReadLanguage(filePath, lang string) (LanguageContent, error) {
+	catalog, err := readCatalog(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.content(lang), nil
+}
+
+// WriteLanguage replaces the localizations of lang in the catalog
+func (m *XCStringsManager) WriteLanguage(filePath, lang string, content LanguageContent) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	catalog, err := readCatalog(filePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		catalog = xcCatalog{
+			"sourceLanguage": lang,
+			"strings":        make(map[string]interface{}),
+			"version":        "1.0",
+		}
+	}
+
+	catalog.setContent(lang, content)
+
+	buffer := &bytes.Buffer{}
+	if err := writeXcodeJSON(buffer, map[string]interface{}(catalog), ""); err != nil {
+		return fmt.Errorf("failed to marshal string catalog %s: %w", filePath, err)
+	}
+
+	// Write to file
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// ShapeContent implements ContentShaper. Plural variations get one form per
+// CLDR plural category of lang, like few and many for Russian. A zero form
+// of the source is kept, since Apple uses it for zero in every language.
+func (m *XCStringsManager) ShapeContent(filePath, lang string, base LanguageContent) LanguageContent {
+	return shapeApplePlurals(base, lang)
+}
+
+// Exists checks if a file exists
+func (m *XCStringsManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// xcCatalog is the decoded JSON of a String Catalog
+type xcCatalog map[string]interface{}
+
+// readCatalog parses a String Catalog
+func readCatalog(filePath string) (xcCatalog, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	var catalog xcCatalog
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal string catalog from %s: %w", filePath, err)
+	}
+	if _, ok := catalog["strings"].(map[string]interface{}); !ok {
+		catalog["strings"] = make(map[string]interface{})
+	}
+	return catalog, nil
+}
+
+// sourceLanguage returns the language the catalog is written in
+func (c xcCatalog) sourceLanguage() string {
+	lang, _ := c["sourceLanguage"].(string)
+	return lang
+}
+
+// strings returns the entries of the catalog keyed by string
+func (c xcCatalog) strings() map[string]interface{} {
+	entries, _ := c["strings"].(map[string]interface{})
+	return entries
+}
+
+// content returns the localizations of lang
+func (c xcCatalog) content(lang string) LanguageContent {
+	content := make(LanguageContent)
+
+	for key, value := range c.strings() {
+		entry, _ := value.(map[string]interface{})
+		if shouldTranslate, ok := entry["shouldTranslate"].(bool); ok && !shouldTranslate {
+			continue
+		}
+
+		localizations, _ := entry["localizations"].(map[string]interface{})
+		localization, ok := localizations[lang].(map[string]interface{})
+		if !ok {
+			// Strings without a localization of the source language use their key
+			if lang == c.sourceLanguage() {
+				content[key] = key
+			}
+			continue
+		}
+
+		if text, ok := stringUnitValue(localization); ok {
+			content[key] = text
+			continue
+		}
+
+		variations, _ := localization["variations"].(map[string]interface{})
+		plural, _ := variations["plural"].(map[string]interface{})
+		forms := make(map[string]interface{})
+		for category, variation := range plural {
+			if variation, ok := variation.(map[string]interface{}); ok {
+				if text, ok := stringUnitValue(variation); ok {
+					forms[category] = text
+				}
+			}
+		}
+		if len(forms) > 0 {
+			content[key] = forms
+		}
+	}
+
+	return content
+}
+
+// setContent replaces the localizations of lang with content
+func (c xcCatalog) setContent(lang string, content LanguageContent) {
+	entries := c.strings()
+
+	for key, value := range entries {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if shouldTranslate, ok := entry["shouldTranslate"].(bool); ok && !shouldTranslate {
+			continue
+		}
+		if _, ok := content[key]; ok {
+			continue
+		}
+		if localizations, ok := entry["localizations"].(map[string]interface{}); ok {
+			delete(localizations, lang)
+		}
+	}
+
+	for key, value := range content {
+		entry, ok := entries[key].(map[string]interface{})
+		if !ok {
+			entry = make(map[string]interface{})
+			entries[key] = entry
+		}
+
+		localizations, ok := entry["localizations"].(map[string]interface{})
+		if !ok {
+			localizations = make(map[string]interface{})
+		}
+
+		// Source strings are their own key until they are edited
+		if _, exists := localizations[lang]; !exists && lang == c.sourceLanguage() && value == key {
+			continue
+		}
+
+		// Keep the state of translations that did not change
+		if existing, ok := localizations[lang].(map[string]interface{}); ok {
+			if text, ok := stringUnitValue(existing); ok && value == text {
+				continue
+			}
+		}
+
+		if nested, ok := AsContent(value); ok {
+			plural := make(map[string]interface{})
+			for category, form := range nested {
+				// Descriptions of plural categories are not part of the catalog
+				if !IsMetadataKey(category) {
+					plural[category] = map[string]interface{}{"stringUnit": newStringUnit(fmt.Sprint(form))}
+				}
+			}
+			localizations[lang] = map[string]interface{}{
+				"variations": map[string]interface{}{"plural": plural},
+			}
+		} else {
+			localizations[lang] = map[string]interface{}{"stringUnit": newStringUnit(fmt.Sprint(value))}
+		}
+		entry["localizations"] = localizations
+	}
+}
+
+// stringUnitValue returns the value of the stringUnit of a localization or variation
+func stringUnitValue(localization map[string]interface{}) (string, bool) {
+	unit, ok := localization["stringUnit"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	text, ok := unit["value"].(string)
+	return text, ok
+}
+
+// newStringUnit creates the stringUnit of a translated value
+func newStringUnit(text string) map[string]interface{} {
+	return map[string]interface{}{"state": "translated", "value": text}
+}
+
+// writeXcodeJSON writes value with sorted keys, two space indentation and
+// " : " separators, like Xcode does, to keep diffs of catalogs small
+func writeXcodeJSON(buffer *bytes.Buffer, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			buffer.WriteString("{\n\n" + indent + "}")
+			break
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buffer.WriteString("{\n")
+		for i, key := range keys {
+			buffer.WriteString(indent + "  ")
+			if err := writeXcodeJSON(buffer, key, ""); err != nil {
+				return err
+			}
+			buffer.WriteString(" : ")
+			if err := writeXcodeJSON(buffer, v[key], indent+"  "); err != nil {
+				return err
+			}
+			if i < len(keys)-1 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(indent + "}")
+
+	case []interface{}:
+		buffer.WriteString("[\n")
+		for i, item := range v {
+			buffer.WriteString(indent + "  ")
+			if err := writeXcodeJSON(buffer, item, indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buffer.WriteString(",")
+			}
+			buffer.WriteString("\n")
+		}
+		buffer.WriteString(indent + "]")
+
+	default:
+		encoded := &bytes.Buffer{}
+		encoder := json.NewEncoder(encoded)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		buffer.WriteString(strings.TrimSuffix(encoded.String(), "\n"))
+	}
+
+	return nil
+}
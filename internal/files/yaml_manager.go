@@ -0,0 +1,248 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLManager implements FileManager for YAML files.
+//
+// Files whose only top-level key is their own language code, like the
+// "en:" root of Rails locale files, are unwrapped on Read. Once such a file
+// has been read, new files are written wrapped in their language code too.
+// Writing to an existing file keeps its comments, and its key order unless
+// the content is written with an order of its own.
+type YAMLManager struct {
+	wrapInLanguage bool
+}
+
+// NewYAMLManager creates a new YAMLManager
+func NewYAMLManager() *YAMLManager {
+	return &YAMLManager{}
+}
+
+// Write saves the content to a YAML file
+func (m *YAMLManager) Write(filePath string, content LanguageContent) error {
+	return m.WriteDocument(filePath, &Document{Content: content})
+}
+
+// WriteDocument saves the content to a YAML file with its keys in the order of the document
+func (m *YAMLManager) WriteDocument(filePath string, doc *Document) error {
+	// Ensure directory exists
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	lang := languageFromPath(filePath)
+	wrapped := m.wrapInLanguage
+
+	// Update the existing document in place to keep its order and comments
+	var document yaml.Node
+	data, err := os.ReadFile(filePath)
+	if err == nil && yaml.Unmarshal(data, &document) == nil && len(document.Content) > 0 {
+		wrapped = isWrappedInLanguage(document.Content[0], lang)
+	} else {
+		document = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	root := document.Content[0]
+	if wrapped {
+		if !isWrappedInLanguage(root, lang) {
+			root.Content = []*yaml.Node{stringNode(lang), {Kind: yaml.MappingNode}}
+		}
+		root = root.Content[1]
+	}
+	if root.Kind != yaml.MappingNode {
+		*root = yaml.Node{Kind: yaml.MappingNode}
+	}
+
+	if err := updateMapping(root, doc.Content, doc.Order); err != nil {
+		return fmt.Errorf("failed to marshal YAML content for %s: %w", filePath, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return fmt.Errorf("failed to marshal YAML content for %s: %w", filePath, err)
+	}
+	encoder.Close()
+
+	// Write to file
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Read loads content from a YAML file
+func (m *YAMLManager) Read(filePath string) (LanguageContent, error) {
+	doc, err := m.ReadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content, nil
+}
+
+// ReadDocument loads content from a YAML file along with the order of its keys
+func (m *YAMLManager) ReadDocument(filePath string) (*Document, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+
+	// Decode into a plain map so nested objects are map[string]interface{} like in JSON
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML from %s: %w", filePath, err)
+	}
+	content := LanguageContent(raw)
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML from %s: %w", filePath, err)
+	}
+	var order *KeyOrder
+	if len(document.Content) > 0 {
+		order = mappingOrder(document.Content[0])
+	}
+
+	lang := languageFromPath(filePath)
+	if len(content) == 1 {
+		if nested, ok := AsContent(content[lang]); ok {
+			m.wrapInLanguage = true
+			return &Document{Content: nested, Order: order.Child(lang)}, nil
+		}
+	}
+
+	return &Document{Content: content, Order: order}, nil
+}
+
+// Exists checks if a file exists
+func (m *YAMLManager) Exists(filePath string) (bool, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil // File doesn't exist
+		}
+		return false, fmt.Errorf("failed to check if file %s exists: %w", filePath, err)
+	}
+
+	return !info.IsDir(), nil // Return true if it exists and is not a directory
+}
+
+// updateMapping makes a mapping node hold content. Existing keys keep their
+// comments, removed keys are dropped and new keys are appended in order,
+// followed by the others in sorted order. With an order, keys are then
+// moved to their place in it; without one, existing keys keep their
+// position.
+func updateMapping(node *yaml.Node, content LanguageContent, order *KeyOrder) error {
+	seen := make(map[string]bool)
+	var pairs [][2]*yaml.Node
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		value, ok := content[keyNode.Value]
+		if !ok || seen[keyNode.Value] {
+			continue
+		}
+		seen[keyNode.Value] = true
+
+		if nested, isObject := AsContent(value); isObject && valueNode.Kind == yaml.MappingNode {
+			if err := updateMapping(valueNode, nested, order.Child(keyNode.Value)); err != nil {
+				return err
+			}
+		} else {
+			newNode, err := valueToNode(value, order.Child(keyNode.Value))
+			if err != nil {
+				return err
+			}
+			newNode.HeadComment = valueNode.HeadComment
+			newNode.LineComment = valueNode.LineComment
+			newNode.FootComment = valueNode.FootComment
+			valueNode = newNode
+		}
+
+		pairs = append(pairs, [2]*yaml.Node{keyNode, valueNode})
+	}
+
+	newContent := make(LanguageContent)
+	for key, value := range content {
+		if !seen[key] {
+			newContent[key] = value
+		}
+	}
+	for _, key := range OrderedKeys(newContent, order) {
+		valueNode, err := valueToNode(content[key], order.Child(key))
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, [2]*yaml.Node{stringNode(key), valueNode})
+	}
+
+	reorder(pairs, order, func(pair [2]*yaml.Node) (string, bool) {
+		return pair[0].Value, true
+	})
+
+	node.Content = make([]*yaml.Node, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		node.Content = append(node.Content, pair[0], pair[1])
+	}
+	return nil
+}
+
+// mappingOrder returns the order of the keys of a mapping node and of its nested mappings
+func mappingOrder(node *yaml.Node) *KeyOrder {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	order := &KeyOrder{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		order.Add(node.Content[i].Value, mappingOrder(node.Content[i+1]))
+	}
+	return order
+}
+
+// valueToNode converts a content value into a YAML node with mapping keys in order
+func valueToNode(value interface{}, order *KeyOrder) (*yaml.Node, error) {
+	if nested, ok := AsContent(value); ok {
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if err := updateMapping(node, nested, order); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// stringNode creates a scalar node holding a string
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// isWrappedInLanguage reports whether node is a mapping whose only key is lang
+func isWrappedInLanguage(node *yaml.Node, lang string) bool {
+	return node.Kind == yaml.MappingNode &&
+		len(node.Content) == 2 &&
+		node.Content[0].Value == lang &&
+		node.Content[1].Kind == yaml.MappingNode
+}
+
+// languageFromPath returns the file name without its extension, which is the language code
+func languageFromPath(filePath string) string {
+	base := filepath.Base(filePath)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
@@ -0,0 +1,75 @@
+package icu
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Placeholders returns the sorted, unique names of the arguments referenced by elements,
+// including the arguments of nested plural, select and tag content
+func Placeholders(elements []Element) []string {
+	seen := make(map[string]bool)
+	collectPlaceholders(elements, seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectPlaceholders(elements []Element, seen map[string]bool) {
+	for _, element := range elements {
+		switch e := element.(type) {
+		case ArgumentElement:
+			seen[e.Value] = true
+		case NumberElement:
+			seen[e.Value] = true
+		case DateElement:
+			seen[e.Value] = true
+		case TimeElement:
+			seen[e.Value] = true
+		case SelectElement:
+			seen[e.Value] = true
+			for _, option := range e.Options {
+				collectPlaceholders(option, seen)
+			}
+		case PluralElement:
+			seen[e.Value] = true
+			for _, option := range e.Options {
+				collectPlaceholders(option, seen)
+			}
+		case TagElement:
+			collectPlaceholders(e.Children, seen)
+		}
+	}
+}
+
+// ComparePlaceholders returns an error when translation is not a valid
+// message or references other arguments than source. Sources that are not
+// valid messages have nothing to compare and accept any translation.
+func ComparePlaceholders(source, translation string) error {
+	elements, err := Parse(source)
+	if err != nil {
+		return nil
+	}
+	return CheckPlaceholders(Placeholders(elements), translation)
+}
+
+// CheckPlaceholders returns an error when translation is not a valid message
+// or does not reference exactly the arguments of want, sorted like
+// Placeholders returns them
+func CheckPlaceholders(want []string, translation string) error {
+	elements, err := Parse(translation)
+	if err != nil {
+		return fmt.Errorf("translation is not a valid ICU message: %w", err)
+	}
+	got := Placeholders(elements)
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		return fmt.Errorf("translation uses placeholders [%s] instead of [%s]",
+			strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+	return nil
+}
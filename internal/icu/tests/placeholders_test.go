@@ -0,0 +1,77 @@
+package icu_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/icu"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []string
+	}{
+		{message: "Hello, World!", want: []string{}},
+		{message: "Hello, {name}!", want: []string{"name"}},
+		{message: "{count, number} items on {day, date, short}", want: []string{"count", "day"}},
+		{message: "{gender, select, male {He met {name}} other {They met {name}}}", want: []string{"gender", "name"}},
+		{message: "{count, plural, one {# <b>{item}</b>} other {# items}}", want: []string{"count", "item"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			elements, err := icu.Parse(tt.message)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got := icu.Placeholders(elements)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Placeholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparePlaceholders(t *testing.T) {
+	// The parser only rejects messages nested too deeply
+	invalid := strings.Repeat("{n, select, other {", 102) + "x" + strings.Repeat("}}", 102)
+
+	tests := []struct {
+		name        string
+		source      string
+		translation string
+		wantErr     string
+	}{
+		{name: "same placeholders", source: "Hello, {name}!", translation: "Bonjour, {name} !"},
+		{name: "missing placeholder", source: "Hello, {name}!", translation: "Bonjour !", wantErr: "translation uses placeholders [] instead of [name]"},
+		{name: "renamed placeholder", source: "Hello, {name}!", translation: "Bonjour, {nom} !", wantErr: "translation uses placeholders [nom] instead of [name]"},
+		{name: "invalid translation", source: "Hello, {name}!", translation: invalid, wantErr: "translation is not a valid ICU message"},
+		{name: "invalid source", source: invalid, translation: "Bonjour !"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := icu.ComparePlaceholders(tt.source, tt.translation)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ComparePlaceholders() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ComparePlaceholders() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	if err := icu.CheckPlaceholders([]string{"count", "name"}, "{count, plural, one {# for {name}} other {# for {name}}}"); err != nil {
+		t.Errorf("CheckPlaceholders() error = %v", err)
+	}
+	if err := icu.CheckPlaceholders([]string{"name"}, "Bonjour"); err == nil {
+		t.Errorf("CheckPlaceholders() accepted a translation without placeholders")
+	}
+}
@@ -0,0 +1,338 @@
+package lock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+// DefaultFileName is the lock file used when the configuration does not name one
+const DefaultFileName = "globify.lock"
+
+// Version is the lock file format version written by this package. Version
+// 1 files joined keys with plain dots, without escaping the dots in keys.
+const Version = 2
+
+// Entry records the state of a single translated key
+type Entry struct {
+	// Hash is the fingerprint of the base language string the translation was produced from
+	Hash string `json:"hash"`
+	// Approved is set for translations reviewed by a human, like those imported from XLIFF
+	Approved bool `json:"approved,omitempty"`
+}
+
+// Lock records, per target language and key, which base language string
+// each existing translation was produced from. Keys are locked under their
+// path from files.KeyPath, nested keys joined with dots and dots in keys
+// escaped with a backslash.
+type Lock struct {
+	Version   int                         `json:"version"`
+	Languages map[string]map[string]Entry `json:"languages"`
+
+	// legacy holds the languages whose entries still use version 1 paths
+	legacy map[string]bool
+}
+
+// New creates an empty Lock
+func New() *Lock {
+	return &Lock{
+		Version:   Version,
+		Languages: make(map[string]map[string]Entry),
+	}
+}
+
+// Load reads a lock file, returning an empty Lock if the file does not exist
+func Load(filePath string) (*Lock, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return New(), nil
+		}
+		return nil, fmt.Errorf("failed to read lock file %s: %w", filePath, err)
+	}
+
+	l := New()
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", filePath, err)
+	}
+
+	if l.Version > Version {
+		return nil, fmt.Errorf("lock file %s has unsupported version %d", filePath, l.Version)
+	}
+	if l.Languages == nil {
+		l.Languages = make(map[string]map[string]Entry)
+	}
+	if l.Version < Version {
+		l.legacy = make(map[string]bool, len(l.Languages))
+		for lang := range l.Languages {
+			l.legacy[lang] = true
+		}
+	}
+
+	return l, nil
+}
+
+// Save writes the lock to a file. Locks with languages that were not
+// updated since they were loaded from a version 1 file keep that version.
+func (l *Lock) Save(filePath string) error {
+	l.Version = Version
+	if len(l.legacy) > 0 {
+		l.Version = 1
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(l); err != nil {
+		return fmt.Errorf("failed to marshal lock file %s: %w", filePath, err)
+	}
+
+	if err := os.WriteFile(filePath, buffer.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write lock file %s: %w", filePath, err)
+	}
+
+	return nil
+}
+
+// Hash returns the fingerprint stored for a base language string
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Pending returns the part of base that has to be translated into lang.
+//
+// A string is up to date when the previous translation exists and either
+// the lock records the same source fingerprint for it, or the lock has no
+// entry for it and the previous translation differs from the source (an
+// existing human or machine translation made before the key was locked).
+// Metadata keys are always included so processors can use them. Array items
+// are compared with the previous translation at the same index and are
+// locked under paths like "steps.0"; pending arrays are returned as objects
+// keyed by index holding only their pending items, which Merge puts back in
+// place.
+func (l *Lock) Pending(lang string, base, previous files.LanguageContent) files.LanguageContent {
+	return l.pending(l.Languages[lang], l.legacy[lang], "", base, previous)
+}
+
+func (l *Lock) pending(entries map[string]Entry, legacy bool, prefix string, base, previous files.LanguageContent) files.LanguageContent {
+	result := make(files.LanguageContent)
+
+	for key, value := range base {
+		if files.IsMetadataKey(key) {
+			result[key] = value
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+		prevValue := previous[key]
+
+		switch v := value.(type) {
+		case string:
+			prevString, ok := prevValue.(string)
+			if !ok {
+				result[key] = v
+				continue
+			}
+
+			entry, locked := lookup(entries, legacy, prefix, key)
+			if locked && entry.Hash == Hash(v) {
+				continue
+			}
+			if !locked && prevString != v {
+				continue
+			}
+			result[key] = v
+
+		default:
+			nested, prevNested, ok := children(value, prevValue)
+			if !ok {
+				continue
+			}
+			if pending := l.pending(entries, legacy, path, nested, prevNested); hasTranslatable(pending) {
+				// Processors expect nested objects as decoded from JSON
+				result[key] = map[string]interface{}(pending)
+			}
+		}
+	}
+
+	return result
+}
+
+// Update replaces the entries of lang with the strings of base that have a
+// translation in result, including translations identical to their source,
+// like brand names. Entries approved for the same source stay approved.
+// Strings that failed to translate are put back with Restore.
+func (l *Lock) Update(lang string, base, result files.LanguageContent) {
+	entries := make(map[string]Entry)
+	l.update(l.Languages[lang], l.legacy[lang], entries, "", base, result)
+	l.Languages[lang] = entries
+	delete(l.legacy, lang)
+}
+
+// Approve records the translation of the string at path as reviewed by a
+// human for the given source
+func (l *Lock) Approve(lang, path, source string) {
+	if l.Languages[lang] == nil {
+		l.Languages[lang] = make(map[string]Entry)
+	}
+	l.Languages[lang][path] = Entry{Hash: Hash(source), Approved: true}
+}
+
+// Restore sets the entries of lang at paths back to those of old, the
+// entries of lang before an Update, removing the paths old has no entry
+// for. Runs use it for the keys that failed to translate or that an
+// interruption left unfinished, so those keys stay pending instead of
+// locking their source or stale translation.
+func (l *Lock) Restore(lang string, old map[string]Entry, paths []string) {
+	entries := l.Languages[lang]
+	if entries == nil {
+		entries = make(map[string]Entry)
+		l.Languages[lang] = entries
+	}
+	for _, path := range paths {
+		if entry, ok := old[path]; ok {
+			entries[path] = entry
+		} else {
+			delete(entries, path)
+		}
+	}
+}
+
+func (l *Lock) update(previous map[string]Entry, legacy bool, entries map[string]Entry, prefix string, base, result files.LanguageContent) {
+	for key, value := range base {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+
+		path := files.KeyPath(prefix, key)
+
+		switch v := value.(type) {
+		case string:
+			if _, ok := result[key].(string); !ok {
+				continue
+			}
+			if entry, _ := lookup(previous, legacy, prefix, key); entry.Approved && entry.Hash == Hash(v) {
+				entries[path] = entry
+			} else {
+				entries[path] = Entry{Hash: Hash(v)}
+			}
+
+		default:
+			nested, resultNested, ok := children(value, result[key])
+			if !ok {
+				continue
+			}
+			l.update(previous, legacy, entries, path, nested, resultNested)
+		}
+	}
+}
+
+// Merge builds the content of a target file from the base content, the
+// previous translations and the freshly translated pending content. Keys
+// missing from base are dropped.
+func Merge(base, previous, translated files.LanguageContent) files.LanguageContent {
+	result := make(files.LanguageContent)
+
+	for key, value := range base {
+		if files.IsMetadataKey(key) {
+			result[key] = value
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if translatedValue, ok := translated[key]; ok {
+				result[key] = translatedValue
+			} else if prevValue, ok := previous[key].(string); ok {
+				result[key] = prevValue
+			} else {
+				result[key] = v
+			}
+
+		default:
+			if items, ok := value.([]interface{}); ok {
+				itemsContent, prevItems, _ := children(items, previous[key])
+				// Pending arrays are translated as objects keyed by index
+				translatedItems, ok := files.AsContent(translated[key])
+				if !ok {
+					translatedItems, _ = files.ArrayContent(translated[key])
+				}
+				result[key] = files.ContentArray(Merge(itemsContent, prevItems, translatedItems), len(items))
+				continue
+			}
+
+			nested, ok := files.AsContent(value)
+			if !ok {
+				// Keep other values, like numbers and booleans, as they are
+				result[key] = value
+				continue
+			}
+			prevNested, _ := files.AsContent(previous[key])
+			translatedNested, _ := files.AsContent(translated[key])
+			result[key] = Merge(nested, prevNested, translatedNested)
+		}
+	}
+
+	return result
+}
+
+// legacyUnescaper reverses the escaping of files.KeyPath
+var legacyUnescaper = strings.NewReplacer(`\\`, `\`, `\.`, ".")
+
+// lookup returns the entry of key below the path prefix. The entries of
+// legacy languages, loaded from a version 1 file, are also looked up under
+// the plain dotted path until the next Update rewrites them.
+func lookup(entries map[string]Entry, legacy bool, prefix, key string) (Entry, bool) {
+	path := files.KeyPath(prefix, key)
+	if entry, ok := entries[path]; ok || !legacy {
+		return entry, ok
+	}
+	legacyPath := key
+	if prefix != "" {
+		legacyPath = legacyUnescaper.Replace(prefix) + "." + key
+	}
+	if legacyPath == path {
+		return Entry{}, false
+	}
+	entry, ok := entries[legacyPath]
+	return entry, ok
+}
+
+// children returns the items of a nested object or array of base, keyed like
+// an object, along with those of the value at the same key of other
+func children(base, other interface{}) (files.LanguageContent, files.LanguageContent, bool) {
+	if nested, ok := files.AsContent(base); ok {
+		otherNested, _ := files.AsContent(other)
+		return nested, otherNested, true
+	}
+	if items, ok := files.ArrayContent(base); ok {
+		otherItems, _ := files.ArrayContent(other)
+		return items, otherItems, true
+	}
+	return nil, nil, false
+}
+
+// hasTranslatable reports whether content holds anything besides metadata
+func hasTranslatable(content files.LanguageContent) bool {
+	for key := range content {
+		if !files.IsMetadataKey(key) {
+			return true
+		}
+	}
+	return false
+}
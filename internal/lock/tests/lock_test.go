@@ -0,0 +1,371 @@
+package lock_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/lock"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lock-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	l, err := lock.Load(filepath.Join(tempDir, lock.DefaultFileName))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(l.Languages) != 0 {
+		t.Errorf("Load() of missing file returned %d languages, want 0", len(l.Languages))
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lock-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	base := files.LanguageContent{
+		"greeting": "Hello",
+		"nested": map[string]interface{}{
+			"key1": "Nested value 1",
+		},
+	}
+	result := files.LanguageContent{
+		"greeting": "Bonjour",
+		"nested": map[string]interface{}{
+			"key1": "Valeur 1",
+		},
+	}
+
+	l := lock.New()
+	l.Update("fr", base, result)
+
+	lockPath := filepath.Join(tempDir, lock.DefaultFileName)
+	if err := l.Save(lockPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := map[string]lock.Entry{
+		"greeting":    {Hash: lock.Hash("Hello")},
+		"nested.key1": {Hash: lock.Hash("Nested value 1")},
+	}
+	if !reflect.DeepEqual(loaded.Languages["fr"], want) {
+		t.Errorf("Load() entries = %v, want %v", loaded.Languages["fr"], want)
+	}
+
+	// Invalid content
+	if err := os.WriteFile(lockPath, []byte("invalid"), 0644); err != nil {
+		t.Fatalf("Failed to write invalid lock file: %v", err)
+	}
+	if _, err := lock.Load(lockPath); err == nil {
+		t.Errorf("Load() with invalid content should return error")
+	}
+}
+
+func TestPending(t *testing.T) {
+	base := files.LanguageContent{
+		"unchanged": "Hello",
+		"changed":   "Goodbye for now",
+		"new":       "Welcome",
+		"human":     "Thanks",
+		"failed":    "Retry me",
+		"nested": map[string]interface{}{
+			"same":  "Nested value",
+			"fresh": "Nested fresh",
+		},
+		"@unchanged": map[string]interface{}{
+			"description": "A greeting",
+		},
+		"number": 42,
+	}
+	previous := files.LanguageContent{
+		"unchanged": "Bonjour",
+		"changed":   "Au revoir",
+		"human":     "Merci",
+		"failed":    "Retry me",
+		"nested": map[string]interface{}{
+			"same": "Valeur imbriquée",
+		},
+	}
+
+	l := lock.New()
+	l.Languages["fr"] = map[string]lock.Entry{
+		"unchanged":   {Hash: lock.Hash("Hello")},
+		"changed":     {Hash: lock.Hash("Goodbye")},
+		"nested.same": {Hash: lock.Hash("Nested value")},
+	}
+
+	pending := l.Pending("fr", base, previous)
+
+	want := files.LanguageContent{
+		"changed": "Goodbye for now",
+		"new":     "Welcome",
+		"failed":  "Retry me",
+		"nested": map[string]interface{}{
+			"fresh": "Nested fresh",
+		},
+		"@unchanged": map[string]interface{}{
+			"description": "A greeting",
+		},
+	}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("Pending() = %v, want %v", pending, want)
+	}
+
+	// Nothing is pending once everything is translated and locked
+	translated := lock.Merge(base, previous, files.LanguageContent{
+		"changed": "Au revoir pour l'instant",
+		"new":     "Bienvenue",
+		"failed":  "Réessaie",
+		"nested": files.LanguageContent{
+			"fresh": "Valeur fraîche",
+		},
+	})
+	l.Update("fr", base, translated)
+
+	pending = l.Pending("fr", base, translated)
+	want = files.LanguageContent{
+		"@unchanged": map[string]interface{}{
+			"description": "A greeting",
+		},
+	}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("Pending() after Update() = %v, want %v", pending, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := files.LanguageContent{
+		"greeting": "Hello",
+		"farewell": "Goodbye",
+		"nested": map[string]interface{}{
+			"key1": "Nested value 1",
+			"key2": "Nested value 2",
+		},
+		"@greeting": "metadata",
+		"number":    42,
+	}
+	previous := files.LanguageContent{
+		"greeting": "Bonjour",
+		"removed":  "Supprimé",
+		"nested": map[string]interface{}{
+			"key1": "Valeur 1",
+		},
+	}
+	translated := files.LanguageContent{
+		"farewell": "Au revoir",
+		"nested": files.LanguageContent{
+			"key2": "Valeur 2",
+		},
+	}
+
+	got := lock.Merge(base, previous, translated)
+
+	want := files.LanguageContent{
+		"greeting": "Bonjour",
+		"farewell": "Au revoir",
+		"nested": files.LanguageContent{
+			"key1": "Valeur 1",
+			"key2": "Valeur 2",
+		},
+		"@greeting": "metadata",
+		"number":    42,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+}
+
+func TestPendingArrays(t *testing.T) {
+	base := files.LanguageContent{
+		"steps": []interface{}{"Sign up", "Verify", "Done"},
+	}
+	previous := files.LanguageContent{
+		"steps": []interface{}{"S'inscrire", "Vérifier"},
+	}
+
+	l := lock.New()
+	l.Languages["fr"] = map[string]lock.Entry{
+		"steps.0": {Hash: lock.Hash("Sign up")},
+		"steps.1": {Hash: lock.Hash("Check")},
+	}
+
+	// Only the changed and the new item are pending, keyed by index
+	pending := l.Pending("fr", base, previous)
+	want := files.LanguageContent{
+		"steps": map[string]interface{}{"1": "Verify", "2": "Done"},
+	}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("Pending() = %v, want %v", pending, want)
+	}
+
+	translated := lock.Merge(base, previous, files.LanguageContent{
+		"steps": map[string]interface{}{"1": "Vérifier", "2": "Terminé"},
+	})
+	wantMerged := files.LanguageContent{
+		"steps": []interface{}{"S'inscrire", "Vérifier", "Terminé"},
+	}
+	if !reflect.DeepEqual(translated, wantMerged) {
+		t.Errorf("Merge() = %v, want %v", translated, wantMerged)
+	}
+
+	l.Update("fr", base, translated)
+	if _, ok := l.Languages["fr"]["steps.2"]; !ok {
+		t.Errorf("Update() did not lock steps.2: %v", l.Languages["fr"])
+	}
+	if pending := l.Pending("fr", base, translated); len(pending) != 0 {
+		t.Errorf("Pending() after update = %v, want nothing", pending)
+	}
+}
+
+func TestApprove(t *testing.T) {
+	base := files.LanguageContent{
+		"ok":       "OK",
+		"greeting": "Hello",
+	}
+
+	l := lock.New()
+	l.Approve("fr", "ok", "OK")
+	l.Approve("fr", "greeting", "Hi")
+
+	// Approved translations identical to their source stay locked, those
+	// approved for an older source are replaced
+	l.Update("fr", base, files.LanguageContent{"ok": "OK", "greeting": "Bonjour"})
+
+	want := map[string]lock.Entry{
+		"ok":       {Hash: lock.Hash("OK"), Approved: true},
+		"greeting": {Hash: lock.Hash("Hello")},
+	}
+	if !reflect.DeepEqual(l.Languages["fr"], want) {
+		t.Errorf("Update() entries = %v, want %v", l.Languages["fr"], want)
+	}
+
+	if pending := l.Pending("fr", base, files.LanguageContent{"ok": "OK", "greeting": "Bonjour"}); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want nothing to translate", pending)
+	}
+}
+
+func TestUpdateLocksIdenticalTranslations(t *testing.T) {
+	base := files.LanguageContent{"brand": "Globify", "greeting": "Hello"}
+	translated := files.LanguageContent{"brand": "Globify", "greeting": "Bonjour"}
+
+	l := lock.New()
+	l.Update("fr", base, translated)
+	if _, ok := l.Languages["fr"]["brand"]; !ok {
+		t.Errorf("Update() did not lock a translation identical to its source: %v", l.Languages["fr"])
+	}
+	if pending := l.Pending("fr", base, translated); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want nothing to translate", pending)
+	}
+
+	// Restored failures stay pending
+	l.Restore("fr", nil, []string{"brand"})
+	if pending := l.Pending("fr", base, translated); !reflect.DeepEqual(pending, files.LanguageContent{"brand": "Globify"}) {
+		t.Errorf("Pending() after Restore() = %v, want the brand only", pending)
+	}
+}
+
+func TestDottedKeys(t *testing.T) {
+	// A flat key with a dot and the nested key of the same text are locked apart
+	base := files.LanguageContent{
+		"a.b": "Flat",
+		"a": map[string]interface{}{
+			"b": "Nested",
+		},
+	}
+	translated := files.LanguageContent{
+		"a.b": "Plat",
+		"a": map[string]interface{}{
+			"b": "Imbriqué",
+		},
+	}
+
+	l := lock.New()
+	l.Update("fr", base, translated)
+
+	want := map[string]lock.Entry{
+		`a\.b`: {Hash: lock.Hash("Flat")},
+		"a.b":  {Hash: lock.Hash("Nested")},
+	}
+	if !reflect.DeepEqual(l.Languages["fr"], want) {
+		t.Errorf("Update() entries = %v, want %v", l.Languages["fr"], want)
+	}
+
+	base["a.b"] = "Flat changed"
+	pending := l.Pending("fr", base, translated)
+	if wantPending := (files.LanguageContent{"a.b": "Flat changed"}); !reflect.DeepEqual(pending, wantPending) {
+		t.Errorf("Pending() = %v, want %v", pending, wantPending)
+	}
+}
+
+func TestLoadVersion1(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "lock-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Version 1 files joined keys with plain dots
+	lockPath := filepath.Join(tempDir, lock.DefaultFileName)
+	data := `{"version": 1, "languages": {"fr": {"Done.": {"hash": "` + lock.Hash("Done.") + `"}, "nested.key": {"hash": "` + lock.Hash("Nested") + `"}}}}`
+	if err := os.WriteFile(lockPath, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+
+	l, err := lock.Load(lockPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	base := files.LanguageContent{
+		"Done.":  "Done.",
+		"nested": map[string]interface{}{"key": "Nested"},
+	}
+	translated := files.LanguageContent{
+		"Done.":  "Done.",
+		"nested": map[string]interface{}{"key": "Imbriqué"},
+	}
+	if pending := l.Pending("fr", base, translated); len(pending) != 0 {
+		t.Errorf("Pending() = %v, want nothing to translate", pending)
+	}
+
+	// Languages keep the version 1 paths until they are updated
+	if err := l.Save(lockPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if l, err = lock.Load(lockPath); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if l.Version != 1 {
+		t.Errorf("Save() before Update() wrote version %d, want 1", l.Version)
+	}
+
+	l.Update("fr", base, translated)
+	if err := l.Save(lockPath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if l, err = lock.Load(lockPath); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]lock.Entry{
+		`Done\.`:     {Hash: lock.Hash("Done.")},
+		"nested.key": {Hash: lock.Hash("Nested")},
+	}
+	if l.Version != lock.Version || !reflect.DeepEqual(l.Languages["fr"], want) {
+		t.Errorf("Load() after Update() = version %d, entries %v, want version %d, entries %v", l.Version, l.Languages["fr"], lock.Version, want)
+	}
+}
@@ -0,0 +1,32 @@
+package printf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Regex matches printf style placeholders like %s, %1$s, %d, %.2f, %@
+// or %lld and .stringsdict variables like %#@count@, as used by Android
+// resources, gettext catalogs and Apple strings
+var Regex = regexp.MustCompile(`%(?:\d+\$)?(?:#@\w+@|[-#+0,(]*\d*(?:\.\d+)?(?:hh|h|ll|l|q|z|t|j)?[sSdDfeEgGxXoOcCbuU@%])`)
+
+// Placeholders returns the sorted printf placeholders of text, once per use
+func Placeholders(text string) []string {
+	placeholders := Regex.FindAllString(text, -1)
+	sort.Strings(placeholders)
+	return placeholders
+}
+
+// ComparePlaceholders returns an error when translation does not use the
+// printf placeholders of source, in any order
+func ComparePlaceholders(source, translation string) error {
+	want := Placeholders(source)
+	got := Placeholders(translation)
+	if strings.Join(want, ",") != strings.Join(got, ",") {
+		return fmt.Errorf("translation uses printf placeholders [%s] instead of [%s]",
+			strings.Join(got, ", "), strings.Join(want, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package printf_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/printf"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "Hello", want: nil},
+		{text: "Saved %d files in %s", want: []string{"%d", "%s"}},
+		{text: "%2$s sent %1$d messages", want: []string{"%1$d", "%2$s"}},
+		{text: "%.2f%% of %lld, %@ and %#@count@", want: []string{"%#@count@", "%%", "%.2f", "%@", "%lld"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := printf.Placeholders(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Placeholders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparePlaceholders(t *testing.T) {
+	tests := []struct {
+		source      string
+		translation string
+		wantErr     bool
+	}{
+		{source: "Saved %d files", translation: "%d fichiers enregistrés"},
+		{source: "%1$s sent %2$d", translation: "%2$d envoyés par %1$s"},
+		{source: "Saved %d files", translation: "Fichiers enregistrés", wantErr: true},
+		{source: "Hello %s", translation: "Bonjour %d", wantErr: true},
+		{source: "Hello %s", translation: "Bonjour %s %s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source+" => "+tt.translation, func(t *testing.T) {
+			err := printf.ComparePlaceholders(tt.source, tt.translation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ComparePlaceholders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sort"
@@ -36,20 +37,50 @@ func (p *ASTProcessor) SetWorkerPoolSize(count int) {
 
 // Execute translates content with ICU message format strings
 func (p *ASTProcessor) Execute(
+	ctx context.Context,
 	obj files.LanguageContent,
 	from, target string,
 	previousTranslation files.LanguageContent,
 ) (files.LanguageContent, error) {
+	result, _, err := p.ExecuteWithFailures(ctx, obj, from, target, previousTranslation)
+	return result, err
+}
+
+// ExecuteWithFailures implements the FailureReporter interface
+func (p *ASTProcessor) ExecuteWithFailures(
+	ctx context.Context,
+	obj files.LanguageContent,
+	from, target string,
+	previousTranslation files.LanguageContent,
+) (files.LanguageContent, []string, error) {
 	// Create a semaphore to limit concurrency
 	sem := make(chan struct{}, p.workerPoolSize)
-	return p.executeInternal(obj, from, target, previousTranslation, sem)
+
+	// Fatal translator errors, like a rejected API key, stop the whole run
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+
+	// Translate the strings in batches first when the translator supports it
+	batched := *p
+	batched.translator = prefetch(ctx, p.translator, obj, from, target, previousTranslation, messageFragments)
+	failed := &failures{}
+	result, err := batched.executeInternal(ctx, obj, from, target, previousTranslation, "", sem, abort, failed)
+	if err != nil && ctx.Err() != nil {
+		// Report why the run stopped, a fatal error or the cancellation of ctx
+		err = context.Cause(ctx)
+	}
+	return result, failed.sorted(), err
 }
 
 func (p *ASTProcessor) executeInternal(
+	ctx context.Context,
 	obj files.LanguageContent,
 	from, target string,
 	previousTranslation files.LanguageContent,
+	prefix string,
 	sem chan struct{},
+	abort context.CancelCauseFunc,
+	failed *failures,
 ) (files.LanguageContent, error) {
 	result := make(files.LanguageContent)
 	var mu sync.Mutex
@@ -80,13 +111,46 @@ func (p *ASTProcessor) executeInternal(
 				continue
 			}
 
+			// Stop sending keys to the translator once the run is cancelled
+			if ctx.Err() != nil {
+				continue
+			}
+
 			wg.Add(1)
-			go func(k, val string) {
+			go func(k, path, val, description string) {
 				defer wg.Done()
 				
-				// Acquire semaphore
-				sem <- struct{}{}
+				// Acquire semaphore, unless the run stops while waiting
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
 				defer func() { <-sem }()
+				if ctx.Err() != nil {
+					return
+				}
+
+				// Translators handling ICU syntax themselves get the whole message
+				if _, whole := translator.ForLanguage(p.translator, target).(translator.MessageTranslator); whole {
+					translated, err := translateText(ctx, p.translator, translator.Message{Key: path, Text: val, Description: description}, from, target)
+					if err != nil && stopped(ctx, abort, err) {
+						return // Leave the key unfinished when the run stopped
+					}
+					if err != nil {
+						log.Printf("Warning: Failed to translate key '%s': %v", k, err)
+						failed.add(path)
+						translated = val // Keep original in case of error
+					} else if err := checkPlaceholders(obj, k, val, translated); err != nil {
+						log.Printf("Warning: Discarding translation of key '%s': %v", k, err)
+						failed.add(path)
+						translated = val
+					}
+					mu.Lock()
+					result[k] = translated
+					mu.Unlock()
+					return
+				}
 
 				// Parse the message string into AST
 				ast, err := icu.Parse(val)
@@ -94,9 +158,13 @@ func (p *ASTProcessor) executeInternal(
 					log.Printf("Warning: Failed to parse ICU message for key '%s': %v", k, err)
 
 					// Fall back to simple translation
-					translated, err := p.translator.Translate(val, from, target)
+					translated, err := translateText(ctx, p.translator, translator.Message{Key: path, Text: val, Description: description}, from, target)
+					if err != nil && stopped(ctx, abort, err) {
+						return
+					}
 					if err != nil {
 						log.Printf("Warning: Failed to translate key '%s': %v", k, err)
+						failed.add(path)
 						mu.Lock()
 						result[k] = val // Keep original in case of error
 						mu.Unlock()
@@ -109,18 +177,30 @@ func (p *ASTProcessor) executeInternal(
 				}
 
 				// Translate the AST
-				translatedMessage, err := p.translateElements(ast, from, target)
+				translatedMessage, err := p.translateElements(ctx, ast, description, from, target)
+				if err != nil && stopped(ctx, abort, err) {
+					return
+				}
 				if err != nil {
 					log.Printf("Warning: Failed to translate AST for key '%s': %v", k, err)
+					failed.add(path)
 					mu.Lock()
 					result[k] = val // Keep original in case of error
 					mu.Unlock()
 					return
 				}
+				if err := checkPlaceholders(obj, k, val, translatedMessage); err != nil {
+					log.Printf("Warning: Discarding translation of key '%s': %v", k, err)
+					failed.add(path)
+					mu.Lock()
+					result[k] = val
+					mu.Unlock()
+					return
+				}
 				mu.Lock()
 				result[k] = translatedMessage
 				mu.Unlock()
-			}(key, v)
+			}(key, files.KeyPath(prefix, key), v, messageDescription(obj, key))
 			
 		case map[string]interface{}:
 			// Handle nested objects
@@ -136,8 +216,8 @@ func (p *ASTProcessor) executeInternal(
 			}
 			
 			// Recursively translate the nested object
-			nestedResult, err := p.executeInternal(v, from, target, prevMap, sem)
-			if err != nil {
+			nestedResult, err := p.executeInternal(ctx, v, from, target, prevMap, files.KeyPath(prefix, key), sem, abort, failed)
+			if err != nil && ctx.Err() == nil {
 				errChan <- fmt.Errorf("failed to translate nested object at key '%s': %w", key, err)
 				continue
 			}
@@ -146,8 +226,22 @@ func (p *ASTProcessor) executeInternal(
 			result[key] = nestedResult
 			mu.Unlock()
 			
+		case []interface{}:
+			// Translate arrays item by item, aligned with the previous translation by index
+			items, _ := files.ArrayContent(v)
+			prevItems, _ := files.ArrayContent(prevValue)
+			itemsResult, err := p.executeInternal(ctx, items, from, target, prevItems, files.KeyPath(prefix, key), sem, abort, failed)
+			if err != nil && ctx.Err() == nil {
+				errChan <- fmt.Errorf("failed to translate array at key '%s': %w", key, err)
+				continue
+			}
+
+			mu.Lock()
+			result[key] = files.ContentArray(itemsResult, len(v))
+			mu.Unlock()
+
 		default:
-			// Keep non-string, non-object values as they are
+			// Keep other values, like numbers and booleans, as they are
 			mu.Lock()
 			result[key] = value
 			mu.Unlock()
@@ -163,17 +257,18 @@ func (p *ASTProcessor) executeInternal(
 			return nil, err
 		}
 	}
-	
-	return result, nil
+
+	// Cancelled runs return what was translated so far without the unfinished keys
+	return result, ctx.Err()
 }
 
 // translateElements translates a slice of ICU elements
-func (p *ASTProcessor) translateElements(elements []icu.Element, from, target string) (string, error) {
+func (p *ASTProcessor) translateElements(ctx context.Context, elements []icu.Element, description, from, target string) (string, error) {
 	var result string
 	
 	// Always use sequential processing to avoid too many requests
 	for _, element := range elements {
-		translated, err := p.translateElement(element, from, target)
+		translated, err := p.translateElement(ctx, element, description, from, target)
 		if err != nil {
 			return "", err
 		}
@@ -184,7 +279,7 @@ func (p *ASTProcessor) translateElements(elements []icu.Element, from, target st
 }
 
 // translateElement translates a single ICU element
-func (p *ASTProcessor) translateElement(element icu.Element, from, target string) (string, error) {
+func (p *ASTProcessor) translateElement(ctx context.Context, element icu.Element, description, from, target string) (string, error) {
 	switch element.Type() {
 	case icu.Literal:
 		// Only translate literal text elements
@@ -193,7 +288,7 @@ func (p *ASTProcessor) translateElement(element icu.Element, from, target string
 			return "", nil
 		}
 		
-		translated, err := p.translator.Translate(lit.Value, from, target)
+		translated, err := translateText(ctx, p.translator, translator.Message{Text: lit.Value, Description: description}, from, target)
 		if err != nil {
 			return "", fmt.Errorf("failed to translate literal: %w", err)
 		}
@@ -202,7 +297,7 @@ func (p *ASTProcessor) translateElement(element icu.Element, from, target string
 	case icu.Tag:
 		// Handle tag elements by translating their children
 		tag := element.(icu.TagElement)
-		translatedContent, err := p.translateElements(tag.Children, from, target)
+		translatedContent, err := p.translateElements(ctx, tag.Children, description, from, target)
 		if err != nil {
 			return "", fmt.Errorf("failed to translate tag content: %w", err)
 		}
@@ -215,7 +310,7 @@ func (p *ASTProcessor) translateElement(element icu.Element, from, target string
 		
 		// Process each option sequentially
 		for key, option := range sel.Options {
-			translatedOption, err := p.translateElements(option, from, target)
+			translatedOption, err := p.translateElements(ctx, option, description, from, target)
 			if err != nil {
 				return "", fmt.Errorf("failed to translate select option: %w", err)
 			}
@@ -243,7 +338,7 @@ func (p *ASTProcessor) translateElement(element icu.Element, from, target string
 		
 		// Process each option sequentially
 		for key, option := range plural.Options {
-			translatedOption, err := p.translateElements(option, from, target)
+			translatedOption, err := p.translateElements(ctx, option, description, from, target)
 			if err != nil {
 				return "", fmt.Errorf("failed to translate plural option: %w", err)
 			}
@@ -0,0 +1,227 @@
+package processor
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/icu"
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+// batchSize is the number of texts sent to a BatchTranslator at once.
+// Translators split batches further when their API needs it.
+const batchSize = 50
+
+// batchResult is the translation of a text, or the error of its batch
+type batchResult struct {
+	translation string
+	err         error
+}
+
+// batchKey identifies a text along with the description it was translated with
+type batchKey struct {
+	text        string
+	description string
+}
+
+// batchedTranslator answers with the translations of a batch run ahead of
+// the processor, and sends the texts it does not know to its translator
+type batchedTranslator struct {
+	translator   translator.Translator
+	translations map[batchKey]batchResult
+}
+
+// Translate implements the Translator interface
+func (t *batchedTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if result, ok := t.translations[batchKey{text: text}]; ok {
+		return result.translation, result.err
+	}
+	return t.translator.Translate(ctx, text, from, to)
+}
+
+// TranslateWithDescription implements the DescriptionTranslator interface.
+// Texts batched with their description are answered from the batch, the
+// others are sent one by one with their description.
+func (t *batchedTranslator) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
+	described, ok := t.translator.(translator.DescriptionTranslator)
+	if !ok {
+		return t.Translate(ctx, text, from, to)
+	}
+	if result, ok := t.translations[batchKey{text: text, description: description}]; ok {
+		return result.translation, result.err
+	}
+	return described.TranslateWithDescription(ctx, text, description, from, to)
+}
+
+// prefetch translates the texts a processor would send for obj in batches
+// when the translator of the target language is a BatchTranslator, and
+// returns a translator answering with those translations. Other
+// translators, and translators of whole messages, are returned as they are.
+// Batching stops when ctx is cancelled.
+//
+// Translators taking descriptions get the texts of described strings in
+// batches per description when they are a DescriptionBatchTranslator.
+// Otherwise those texts are left out of the batches and sent one by one
+// with their description, which costs a request per text but keeps the
+// description.
+func prefetch(
+	ctx context.Context,
+	t translator.Translator,
+	obj files.LanguageContent,
+	from, target string,
+	previousTranslation files.LanguageContent,
+	fragments func(text string) []string,
+) translator.Translator {
+	batcher, ok := translator.ForLanguage(t, target).(translator.BatchTranslator)
+	if !ok {
+		return t
+	}
+	if _, whole := batcher.(translator.MessageTranslator); whole {
+		return t
+	}
+
+	_, described := batcher.(translator.DescriptionTranslator)
+	describedBatcher, batchesDescribed := batcher.(translator.DescriptionBatchTranslator)
+	groups := make(map[string]map[string]bool)
+	collectTexts(obj, previousTranslation, described, described && !batchesDescribed, fragments, groups)
+
+	descriptions := make([]string, 0, len(groups))
+	for description := range groups {
+		descriptions = append(descriptions, description)
+	}
+	sort.Strings(descriptions)
+
+	batched := &batchedTranslator{translator: batcher, translations: make(map[batchKey]batchResult)}
+	var fatal error
+	for _, description := range descriptions {
+		sorted := make([]string, 0, len(groups[description]))
+		for text := range groups[description] {
+			sorted = append(sorted, text)
+		}
+		sort.Strings(sorted)
+
+		for start := 0; start < len(sorted) && ctx.Err() == nil && fatal == nil; start += batchSize {
+			batch := sorted[start:min(start+batchSize, len(sorted))]
+			var translations []string
+			var err error
+			if description == "" {
+				translations, err = batcher.TranslateBatch(ctx, batch, from, target)
+			} else {
+				translations, err = describedBatcher.TranslateBatchWithDescription(ctx, batch, description, from, target)
+			}
+			if err != nil && ctx.Err() != nil {
+				break // Unfinished texts are sent again, and cancelled, by the processor
+			}
+			if translator.IsFatal(err) {
+				fatal = err
+				break
+			}
+			for i, text := range batch {
+				key := batchKey{text: text, description: description}
+				if err != nil {
+					// Every string of a failed batch reports the error on its own
+					batched.translations[key] = batchResult{err: err}
+				} else {
+					batched.translations[key] = batchResult{translation: translations[i]}
+				}
+			}
+		}
+	}
+
+	if fatal != nil {
+		// The other batches would fail the same way, so their texts fail without being sent
+		for description, texts := range groups {
+			for text := range texts {
+				key := batchKey{text: text, description: description}
+				if _, ok := batched.translations[key]; !ok {
+					batched.translations[key] = batchResult{err: fatal}
+				}
+			}
+		}
+	}
+	return batched
+}
+
+// collectTexts adds the fragments of every string of obj that differs from
+// its previous translation to groups, keyed by the description the string
+// is translated with, mirroring what executeInternal translates. Described
+// strings are grouped by their description when described is set, and left
+// out when skipDescribed is set.
+func collectTexts(
+	obj, previousTranslation files.LanguageContent,
+	described, skipDescribed bool,
+	fragments func(text string) []string,
+	groups map[string]map[string]bool,
+) {
+	for key, value := range obj {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+		prevValue, hasPrevious := previousTranslation[key]
+
+		switch v := value.(type) {
+		case string:
+			if hasPrevious && prevValue == value {
+				continue
+			}
+			var description string
+			if described {
+				description = messageDescription(obj, key)
+			}
+			if description != "" && skipDescribed {
+				continue
+			}
+			for _, fragment := range fragments(v) {
+				if fragment == "" {
+					continue
+				}
+				if groups[description] == nil {
+					groups[description] = make(map[string]bool)
+				}
+				groups[description][fragment] = true
+			}
+		case map[string]interface{}:
+			prevMap, _ := files.AsContent(prevValue)
+			collectTexts(v, prevMap, described, skipDescribed, fragments, groups)
+		case []interface{}:
+			items, _ := files.ArrayContent(v)
+			prevItems, _ := files.ArrayContent(prevValue)
+			collectTexts(items, prevItems, described, skipDescribed, fragments, groups)
+		}
+	}
+}
+
+// messageFragments returns the texts the ASTProcessor would send for a message
+func messageFragments(text string) []string {
+	elements, err := icu.Parse(text)
+	if err != nil {
+		// Unparsable messages are translated as a whole
+		return textFragments(text)
+	}
+	return literalFragments(elements)
+}
+
+// literalFragments returns the texts translateElements would send for elements
+func literalFragments(elements []icu.Element) []string {
+	var fragments []string
+	for _, element := range elements {
+		switch e := element.(type) {
+		case icu.LiteralElement:
+			if e.Value != "" {
+				fragments = append(fragments, textFragments(e.Value)...)
+			}
+		case icu.TagElement:
+			fragments = append(fragments, literalFragments(e.Children)...)
+		case icu.SelectElement:
+			for _, option := range e.Options {
+				fragments = append(fragments, literalFragments(option)...)
+			}
+		case icu.PluralElement:
+			for _, option := range e.Options {
+				fragments = append(fragments, literalFragments(option)...)
+			}
+		}
+	}
+	return fragments
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"unicode/utf8"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+)
+
+// CostEstimator is implemented by processors that can tell how many
+// characters they would send to the translator for some content
+type CostEstimator interface {
+	EstimateCharacters(obj files.LanguageContent) int
+}
+
+// EstimateCharacters returns the number of characters proc would send to the
+// translator for obj. Processors that do not implement CostEstimator are
+// assumed to send every string as a whole.
+func EstimateCharacters(proc ObjectProcessor, obj files.LanguageContent) int {
+	if estimator, ok := proc.(CostEstimator); ok {
+		return estimator.EstimateCharacters(obj)
+	}
+	return countCharacters(obj, utf8.RuneCountInString)
+}
+
+// EstimateCharacters counts the characters of every string to translate, without printf placeholders
+func (p *SimpleProcessor) EstimateCharacters(obj files.LanguageContent) int {
+	return countCharacters(obj, countTextCharacters)
+}
+
+// EstimateCharacters counts the characters of the literal fragments of every message
+func (p *ASTProcessor) EstimateCharacters(obj files.LanguageContent) int {
+	return countCharacters(obj, func(text string) int {
+		total := 0
+		for _, fragment := range messageFragments(text) {
+			total += utf8.RuneCountInString(fragment)
+		}
+		return total
+	})
+}
+
+// countCharacters sums count over every string of obj, skipping metadata keys
+func countCharacters(obj files.LanguageContent, count func(text string) int) int {
+	total := 0
+	for key, value := range obj {
+		if files.IsMetadataKey(key) {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			total += count(v)
+		default:
+			if nested, ok := files.AsContent(value); ok {
+				total += countCharacters(nested, count)
+			} else if items, ok := files.ArrayContent(value); ok {
+				total += countCharacters(items, count)
+			}
+		}
+	}
+	return total
+}
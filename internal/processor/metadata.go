@@ -0,0 +1,43 @@
+package processor
+
+import (
+	"sort"
+
+	"github.com/bernardoforcillo/globify/internal/files"
+	"github.com/bernardoforcillo/globify/internal/icu"
+)
+
+// messageMetadata returns the "@key" metadata of key in obj, as found in ARB files
+func messageMetadata(obj files.LanguageContent, key string) files.LanguageContent {
+	metadata, _ := files.AsContent(obj["@"+key])
+	return metadata
+}
+
+// messageDescription returns the description of key in its "@key" metadata
+func messageDescription(obj files.LanguageContent, key string) string {
+	description, _ := messageMetadata(obj, key)["description"].(string)
+	return description
+}
+
+// checkPlaceholders verifies that the translation of a message with
+// placeholders declared in its "@key" metadata keeps the arguments of its
+// source, or the declared ones when the source is not a valid ICU message.
+// Messages without declared placeholders are not checked.
+func checkPlaceholders(obj files.LanguageContent, key, source, translation string) error {
+	declared, ok := files.AsContent(messageMetadata(obj, key)["placeholders"])
+	if !ok || len(declared) == 0 {
+		return nil
+	}
+
+	var want []string
+	if elements, err := icu.Parse(source); err == nil {
+		want = icu.Placeholders(elements)
+	} else {
+		for name := range declared {
+			want = append(want, name)
+		}
+		sort.Strings(want)
+	}
+
+	return icu.CheckPlaceholders(want, translation)
+}
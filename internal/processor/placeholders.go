@@ -0,0 +1,101 @@
+package processor
+
+import (
+	"context"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bernardoforcillo/globify/internal/printf"
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+// translateText translates the text of a message without sending its printf
+// placeholders to the translator. The text between placeholders is
+// translated fragment by fragment, like the literals of ICU messages, and
+// fragments without letters are kept as they are. The description of the
+// message, if any, is passed to translators that support it, and
+// translators of whole messages get the message as it is.
+func translateText(ctx context.Context, t translator.Translator, message translator.Message, from, to string) (string, error) {
+	if whole, ok := translator.ForLanguage(t, to).(translator.MessageTranslator); ok {
+		return whole.TranslateMessage(ctx, message, from, to)
+	}
+
+	text, description := message.Text, message.Description
+	matches := printf.Regex.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return translate(ctx, t, text, description, from, to)
+	}
+
+	var result string
+	last := 0
+	for _, match := range matches {
+		translated, err := translateFragment(ctx, t, text[last:match[0]], description, from, to)
+		if err != nil {
+			return "", err
+		}
+		result += translated + text[match[0]:match[1]]
+		last = match[1]
+	}
+
+	translated, err := translateFragment(ctx, t, text[last:], description, from, to)
+	if err != nil {
+		return "", err
+	}
+	return result + translated, nil
+}
+
+// translateFragment translates the text between two placeholders
+func translateFragment(ctx context.Context, t translator.Translator, fragment, description, from, to string) (string, error) {
+	if !hasLetters(fragment) {
+		return fragment, nil
+	}
+	return translate(ctx, t, fragment, description, from, to)
+}
+
+// translate sends text to the translator along with its description when the translator supports it
+func translate(ctx context.Context, t translator.Translator, text, description, from, to string) (string, error) {
+	if described, ok := t.(translator.DescriptionTranslator); ok && description != "" {
+		return described.TranslateWithDescription(ctx, text, description, from, to)
+	}
+	return t.Translate(ctx, text, from, to)
+}
+
+// countTextCharacters counts the characters translateText would send to the translator
+func countTextCharacters(text string) int {
+	total := 0
+	for _, fragment := range textFragments(text) {
+		total += utf8.RuneCountInString(fragment)
+	}
+	return total
+}
+
+// textFragments returns the texts translateText would send to the translator
+func textFragments(text string) []string {
+	matches := printf.Regex.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return []string{text}
+	}
+
+	var fragments []string
+	last := 0
+	for _, match := range matches {
+		if fragment := text[last:match[0]]; hasLetters(fragment) {
+			fragments = append(fragments, fragment)
+		}
+		last = match[1]
+	}
+	if fragment := text[last:]; hasLetters(fragment) {
+		fragments = append(fragments, fragment)
+	}
+	return fragments
+}
+
+// hasLetters reports whether text contains anything worth translating
+func hasLetters(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package processor
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/bernardoforcillo/globify/internal/files"
 	"github.com/bernardoforcillo/globify/internal/translator"
@@ -9,7 +12,66 @@ import (
 
 // ObjectProcessor defines the interface for translating language content
 type ObjectProcessor interface {
-	Execute(obj files.LanguageContent, from, target string, previousTranslation files.LanguageContent) (files.LanguageContent, error)
+	Execute(ctx context.Context, obj files.LanguageContent, from, target string, previousTranslation files.LanguageContent) (files.LanguageContent, error)
+}
+
+// FailureReporter is implemented by processors that report the strings
+// they could not translate, which Execute keeps in the source language
+type FailureReporter interface {
+	ObjectProcessor
+	// ExecuteWithFailures translates like Execute and also returns the
+	// dotted paths of the strings that could not be translated
+	ExecuteWithFailures(ctx context.Context, obj files.LanguageContent, from, target string, previousTranslation files.LanguageContent) (files.LanguageContent, []string, error)
+}
+
+// ExecuteDocument translates the content of doc like Execute and returns it
+// as a document with the key order of doc, so the translation can be
+// written in the same order as its source, along with the paths of the
+// strings that failed when the processor is a FailureReporter. When ctx is
+// cancelled the document holds the keys translated so far, along with the
+// error of ctx.
+func ExecuteDocument(ctx context.Context, proc ObjectProcessor, doc *files.Document, from, target string, previousTranslation files.LanguageContent) (*files.Document, []string, error) {
+	var content files.LanguageContent
+	var failed []string
+	var err error
+	if reporter, ok := proc.(FailureReporter); ok {
+		content, failed, err = reporter.ExecuteWithFailures(ctx, doc.Content, from, target, previousTranslation)
+	} else {
+		content, err = proc.Execute(ctx, doc.Content, from, target, previousTranslation)
+	}
+	if content == nil {
+		return nil, nil, err
+	}
+	return &files.Document{Content: content, Order: doc.Order}, failed, err
+}
+
+// failures collects the paths of the strings a processor could not translate
+type failures struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+// add records the path of a string kept in the source language
+func (f *failures) add(path string) {
+	f.mu.Lock()
+	f.paths = append(f.paths, path)
+	f.mu.Unlock()
+}
+
+// sorted returns the recorded paths in sorted order
+func (f *failures) sorted() []string {
+	sort.Strings(f.paths)
+	return f.paths
+}
+
+// stopped reports whether the translation of a key failed because the run
+// stopped. Fatal errors stop the run, so the remaining keys are not sent to
+// a translator that cannot translate them either.
+func stopped(ctx context.Context, abort context.CancelCauseFunc, err error) bool {
+	if translator.IsFatal(err) {
+		abort(err)
+	}
+	return ctx.Err() != nil
 }
 
 // CreateProcessor returns the appropriate processor based on the translation type
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"sync"
@@ -34,20 +35,50 @@ func (p *SimpleProcessor) SetWorkerPoolSize(count int) {
 
 // Execute translates all string values in the content recursively
 func (p *SimpleProcessor) Execute(
+	ctx context.Context,
 	obj files.LanguageContent,
 	from, target string,
 	previousTranslation files.LanguageContent,
 ) (files.LanguageContent, error) {
+	result, _, err := p.ExecuteWithFailures(ctx, obj, from, target, previousTranslation)
+	return result, err
+}
+
+// ExecuteWithFailures implements the FailureReporter interface
+func (p *SimpleProcessor) ExecuteWithFailures(
+	ctx context.Context,
+	obj files.LanguageContent,
+	from, target string,
+	previousTranslation files.LanguageContent,
+) (files.LanguageContent, []string, error) {
 	// Create a semaphore to limit concurrency
 	sem := make(chan struct{}, p.workerPoolSize)
-	return p.executeInternal(obj, from, target, previousTranslation, sem)
+
+	// Fatal translator errors, like a rejected API key, stop the whole run
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+
+	// Translate the strings in batches first when the translator supports it
+	batched := *p
+	batched.translator = prefetch(ctx, p.translator, obj, from, target, previousTranslation, textFragments)
+	failed := &failures{}
+	result, err := batched.executeInternal(ctx, obj, from, target, previousTranslation, "", sem, abort, failed)
+	if err != nil && ctx.Err() != nil {
+		// Report why the run stopped, a fatal error or the cancellation of ctx
+		err = context.Cause(ctx)
+	}
+	return result, failed.sorted(), err
 }
 
 func (p *SimpleProcessor) executeInternal(
+	ctx context.Context,
 	obj files.LanguageContent,
 	from, target string,
 	previousTranslation files.LanguageContent,
+	prefix string,
 	sem chan struct{},
+	abort context.CancelCauseFunc,
+	failed *failures,
 ) (files.LanguageContent, error) {
 	result := make(files.LanguageContent)
 	var mu sync.Mutex
@@ -78,28 +109,52 @@ func (p *SimpleProcessor) executeInternal(
 				continue
 			}
 
+			// Stop sending keys to the translator once the run is cancelled
+			if ctx.Err() != nil {
+				continue
+			}
+
 			wg.Add(1)
-			go func(k, val string) {
+			go func(k, path, val, description string) {
 				defer wg.Done()
 
-				// Acquire semaphore
-				sem <- struct{}{}
+				// Acquire semaphore, unless the run stops while waiting
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
 				defer func() { <-sem }()
+				if ctx.Err() != nil {
+					return
+				}
 
-				// Translate the string
-				translated, err := p.translator.Translate(val, from, target)
+				// Translate the string, keeping its printf placeholders
+				translated, err := translateText(ctx, p.translator, translator.Message{Key: path, Text: val, Description: description}, from, target)
+				if err != nil && stopped(ctx, abort, err) {
+					return // Leave the key unfinished when the run stopped
+				}
 				if err != nil {
 					log.Printf("Warning: Failed to translate key '%s': %v", k, err)
+					failed.add(path)
 					mu.Lock()
 					result[k] = val // Keep original in case of error
 					mu.Unlock()
 					return
 				}
+				if err := checkPlaceholders(obj, k, val, translated); err != nil {
+					log.Printf("Warning: Discarding translation of key '%s': %v", k, err)
+					failed.add(path)
+					mu.Lock()
+					result[k] = val
+					mu.Unlock()
+					return
+				}
 
 				mu.Lock()
 				result[k] = translated
 				mu.Unlock()
-			}(key, v)
+			}(key, files.KeyPath(prefix, key), v, messageDescription(obj, key))
 
 		case map[string]interface{}:
 			// Handle nested objects
@@ -118,8 +173,8 @@ func (p *SimpleProcessor) executeInternal(
 			// Note: We don't launch a goroutine for the nested object itself,
 			// but pass the shared semaphore down so its children can run concurrently
 			// respecting the global limit.
-			nestedResult, err := p.executeInternal(v, from, target, prevMap, sem)
-			if err != nil {
+			nestedResult, err := p.executeInternal(ctx, v, from, target, prevMap, files.KeyPath(prefix, key), sem, abort, failed)
+			if err != nil && ctx.Err() == nil {
 				errChan <- fmt.Errorf("failed to translate nested object at key '%s': %w", key, err)
 				continue
 			}
@@ -128,8 +183,22 @@ func (p *SimpleProcessor) executeInternal(
 			result[key] = nestedResult
 			mu.Unlock()
 
+		case []interface{}:
+			// Translate arrays item by item, aligned with the previous translation by index
+			items, _ := files.ArrayContent(v)
+			prevItems, _ := files.ArrayContent(prevValue)
+			itemsResult, err := p.executeInternal(ctx, items, from, target, prevItems, files.KeyPath(prefix, key), sem, abort, failed)
+			if err != nil && ctx.Err() == nil {
+				errChan <- fmt.Errorf("failed to translate array at key '%s': %w", key, err)
+				continue
+			}
+
+			mu.Lock()
+			result[key] = files.ContentArray(itemsResult, len(v))
+			mu.Unlock()
+
 		default:
-			// Keep non-string, non-object values as they are
+			// Keep other values, like numbers and booleans, as they are
 			mu.Lock()
 			result[key] = value
 			mu.Unlock()
@@ -146,5 +215,6 @@ func (p *SimpleProcessor) executeInternal(
 		}
 	}
 
-	return result, nil
+	// Cancelled runs return what was translated so far without the unfinished keys
+	return result, ctx.Err()
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package processor_test
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"testing"
@@ -39,20 +40,20 @@ func BenchmarkConcurrentSimpleProcessor(b *testing.B) {
 	b.Run("Small-Dataset", func(b *testing.B) {
 		smallContent := createLargeDataset(10, 2)
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(smallContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), smallContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
 	b.Run("Medium-Dataset", func(b *testing.B) {
 		mediumContent := createLargeDataset(50, 3)
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(mediumContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), mediumContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
 	b.Run("Large-Dataset", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
@@ -64,7 +65,7 @@ func BenchmarkConcurrentSimpleProcessor(b *testing.B) {
 			proc.SetWorkerPoolSize(workers)
 			
 			for i := 0; i < b.N; i++ {
-				_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+				_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 			}
 		})
 	}
@@ -97,20 +98,20 @@ func BenchmarkConcurrentASTProcessor(b *testing.B) {
 	b.Run("Small-Dataset", func(b *testing.B) {
 		smallContent := createLargeDataset(10, 1)
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(smallContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), smallContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
 	b.Run("Medium-Dataset", func(b *testing.B) {
 		mediumContent := createLargeDataset(30, 2)
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(mediumContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), mediumContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
 	b.Run("Large-Dataset", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+			_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 		}
 	})
 	
@@ -120,7 +121,7 @@ func BenchmarkConcurrentASTProcessor(b *testing.B) {
 			astProc.SetWorkerPoolSize(workers)
 			
 			for i := 0; i < b.N; i++ {
-				_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+				_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 			}
 		})
 	}
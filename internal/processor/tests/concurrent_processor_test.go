@@ -1,6 +1,7 @@
 package processor_test
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -63,7 +64,7 @@ func TestConcurrentProcessingCorrectness(t *testing.T) {
 	
 	// Create baseline result with a standard processor
 	simpleProc := processor.NewSimpleProcessor(mockTranslator)
-	baselineResult, err := simpleProc.Execute(baseContent, "en", "fr", emptyPrevious)
+	baselineResult, err := simpleProc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 	assert.NoError(t, err, "Baseline execution should not error")
 	
 	// Test with varying worker counts
@@ -74,7 +75,7 @@ func TestConcurrentProcessingCorrectness(t *testing.T) {
 			procWithWorkers.SetWorkerPoolSize(tc.workerSize)
 			
 			// Execute with concurrency
-			concurrentResult, err := procWithWorkers.Execute(baseContent, "en", "fr", emptyPrevious)
+			concurrentResult, err := procWithWorkers.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 			assert.NoError(t, err, "Concurrent execution should not error")
 			
 			// Verify results match the baseline using our compareMaps helper
@@ -101,7 +102,7 @@ func TestConcurrentProcessingCorrectness(t *testing.T) {
 	}
 	
 	// Create baseline result for AST processing
-	astBaselineResult, err := astProc.Execute(astContent, "en", "fr", emptyPrevious)
+	astBaselineResult, err := astProc.Execute(context.Background(), astContent, "en", "fr", emptyPrevious)
 	assert.NoError(t, err, "AST baseline execution should not error")
 	
 	// Test only if we can cast to ASTProcessor to access SetWorkerPoolSize
@@ -112,7 +113,7 @@ func TestConcurrentProcessingCorrectness(t *testing.T) {
 				ap.SetWorkerPoolSize(tc.workerSize)
 				
 				// Execute with concurrency
-				concurrentResult, err := ap.Execute(astContent, "en", "fr", emptyPrevious)
+				concurrentResult, err := ap.Execute(context.Background(), astContent, "en", "fr", emptyPrevious)
 				assert.NoError(t, err, "Concurrent AST execution should not error")
 				
 				// Verify results match the baseline
@@ -146,7 +147,7 @@ func TestConcurrentTranslationFailureHandling(t *testing.T) {
 		proc.SetWorkerPoolSize(4) // Use multiple workers to test concurrent error handling
 		
 		// Execute with concurrency - should not panic even with failures
-		result, _ := proc.Execute(baseContent, "en", "fr", emptyPrevious)
+		result, _ := proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 		// We're expecting some keys to fail but the overall process should complete
 		
 		// We should still get a result with some values, even if some translations failed
@@ -177,7 +178,7 @@ func TestConcurrentTranslationFailureHandling(t *testing.T) {
 			}
 			
 			// Execute with concurrency - should not panic even with failures
-			result, _ := astProc.Execute(astContent, "en", "fr", emptyPrevious)
+			result, _ := astProc.Execute(context.Background(), astContent, "en", "fr", emptyPrevious)
 			// We're expecting some keys to fail but the overall process should complete
 			
 			// We should still get a result with some values, even if some translations failed
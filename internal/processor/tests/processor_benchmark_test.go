@@ -1,6 +1,7 @@
 package processor_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/bernardoforcillo/globify/internal/files"
@@ -41,7 +42,7 @@ func BenchmarkSimpleProcessorExecute(b *testing.B) {
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+		_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 	}
 }
 
@@ -74,7 +75,7 @@ func BenchmarkSimpleProcessorWithPreviousTranslations(b *testing.B) {
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = proc.Execute(baseContent, "en", "fr", previousTranslation)
+		_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", previousTranslation)
 	}
 }
 
@@ -101,7 +102,7 @@ func BenchmarkASTProcessorExecute(b *testing.B) {
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = proc.Execute(baseContent, "en", "fr", emptyPrevious)
+		_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", emptyPrevious)
 	}
 }
 
@@ -132,6 +133,6 @@ func BenchmarkASTProcessorWithPreviousTranslations(b *testing.B) {
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = proc.Execute(baseContent, "en", "fr", previousTranslation)
+		_, _ = proc.Execute(context.Background(), baseContent, "en", "fr", previousTranslation)
 	}
 }
\ No newline at end of file
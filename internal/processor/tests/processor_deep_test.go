@@ -1,6 +1,7 @@
 package processor_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -14,7 +15,7 @@ type mockTranslatorForAST struct {
 	preserveICUElements bool
 }
 
-func (m *mockTranslatorForAST) Translate(text, from, to string) (string, error) {
+func (m *mockTranslatorForAST) Translate(ctx context.Context, text, from, to string) (string, error) {
 	// If we're preserving ICU elements, we need to handle them specially
 	if m.preserveICUElements {
 		// The simplest approach is to just prefix with the language code for testing
@@ -87,7 +88,7 @@ func TestASTProcessorDeep(t *testing.T) {
 			}
 			
 			// Process the content
-			result, err := proc.Execute(content, "en", "fr", files.LanguageContent{})
+			result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
 			if err != nil {
 				t.Fatalf("ASTProcessor.Execute() error = %v", err)
 			}
@@ -134,7 +135,7 @@ func TestASTProcessorWithNestedObjects(t *testing.T) {
 	}
 	
 	// Process the content
-	result, err := proc.Execute(content, "en", "fr", files.LanguageContent{})
+	result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
 	if err != nil {
 		t.Fatalf("ASTProcessor.Execute() error = %v", err)
 	}
@@ -220,7 +221,7 @@ func TestASTProcessorErrorHandling(t *testing.T) {
 	
 	// Execute the processor - it should not return an error at the top level
 	// even though translations fail
-	result, err := proc.Execute(content, "en", "fr", files.LanguageContent{})
+	result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
 	if err != nil {
 		t.Fatalf("ASTProcessor.Execute() returned error = %v", err)
 	}
@@ -1,12 +1,17 @@
 package processor_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/bernardoforcillo/globify/internal/files"
 	"github.com/bernardoforcillo/globify/internal/processor"
+	"github.com/bernardoforcillo/globify/internal/translator"
 )
 
 // MockTranslator implements the translator.Translator interface for testing
@@ -16,7 +21,7 @@ type MockTranslator struct {
 }
 
 // Translate calls the mock function
-func (m *MockTranslator) Translate(text, from, to string) (string, error) {
+func (m *MockTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
 	return m.MockTranslate(text, from, to)
 }
 
@@ -118,7 +123,7 @@ func TestSimpleProcessorExecute(t *testing.T) {
 	}
 	
 	// Execute the processor
-	result, err := proc.Execute(baseContent, "en", "fr", previousTranslation)
+	result, err := proc.Execute(context.Background(), baseContent, "en", "fr", previousTranslation)
 	
 	// Verify no errors
 	if err != nil {
@@ -160,7 +165,7 @@ func TestSimpleProcessorExecuteWithMetadata(t *testing.T) {
 	}
 
 	// Execute the processor
-	result, err := proc.Execute(baseContent, "en", "fr", files.LanguageContent{})
+	result, err := proc.Execute(context.Background(), baseContent, "en", "fr", files.LanguageContent{})
 
 	if err != nil {
 		t.Errorf("Execute() error = %v", err)
@@ -197,7 +202,7 @@ func TestASTProcessorExecuteWithMetadata(t *testing.T) {
 	}
 
 	// Execute the processor
-	result, err := proc.Execute(baseContent, "en", "fr", files.LanguageContent{})
+	result, err := proc.Execute(context.Background(), baseContent, "en", "fr", files.LanguageContent{})
 
 	if err != nil {
 		t.Errorf("Execute() error = %v", err)
@@ -233,7 +238,7 @@ func TestSimpleProcessorExecuteWithErrors(t *testing.T) {
 	}
 	
 	// Execute the processor
-	result, err := proc.Execute(baseContent, "en", "fr", files.LanguageContent{})
+	result, err := proc.Execute(context.Background(), baseContent, "en", "fr", files.LanguageContent{})
 	
 	// Verify no errors at the top level (errors are logged but not returned)
 	if err != nil {
@@ -254,6 +259,43 @@ func TestSimpleProcessorExecuteWithErrors(t *testing.T) {
 	}
 }
 
+func TestProcessorsReportFailures(t *testing.T) {
+	mock := &MockTranslator{
+		MockTranslate: func(text, from, to string) (string, error) {
+			if text == "Broken" {
+				return "", fmt.Errorf("mock translation error")
+			}
+			return text, nil // Like brand names, translated to themselves
+		},
+	}
+	doc := &files.Document{Content: files.LanguageContent{
+		"brand": "Globify",
+		"nested": map[string]interface{}{
+			"broken": "Broken",
+		},
+		"steps": []interface{}{"OK", "Broken"},
+	}}
+
+	processors := map[string]processor.ObjectProcessor{
+		"simple-json": processor.NewSimpleProcessor(mock),
+		"ast-json":    processor.NewASTProcessor(mock),
+	}
+	for name, proc := range processors {
+		t.Run(name, func(t *testing.T) {
+			result, failed, err := processor.ExecuteDocument(context.Background(), proc, doc, "en", "fr", files.LanguageContent{})
+			if err != nil {
+				t.Fatalf("ExecuteDocument() error = %v", err)
+			}
+			if want := []string{"nested.broken", "steps.1"}; !reflect.DeepEqual(failed, want) {
+				t.Errorf("ExecuteDocument() failed = %v, want %v", failed, want)
+			}
+			if result.Content["brand"] != "Globify" {
+				t.Errorf("ExecuteDocument() brand = %v", result.Content["brand"])
+			}
+		})
+	}
+}
+
 func TestCreateProcessor(t *testing.T) {
 	mockTranslator := createMockTranslator()
 	
@@ -287,4 +329,512 @@ func TestCreateProcessor(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestEstimateCharacters(t *testing.T) {
+	content := files.LanguageContent{
+		"greeting": "Hello, {name}!",
+		"nested": map[string]interface{}{
+			"plural": "{count, plural, one {# item} other {# items}}",
+		},
+		"@greeting": map[string]interface{}{
+			"description": "Not translated",
+		},
+		"number": 42,
+	}
+
+	tests := []struct {
+		name            string
+		translationType string
+		want            int
+	}{
+		{
+			name:            "Simple JSON sends whole strings",
+			translationType: "simple-json",
+			want:            len("Hello, {name}!") + len("{count, plural, one {# item} other {# items}}"),
+		},
+		{
+			name:            "AST JSON sends literal fragments",
+			translationType: "ast-json",
+			want:            len("Hello, ") + len("!") + len(" item") + len(" items"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc, err := processor.CreateProcessor(tt.translationType, createMockTranslator())
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			if got := processor.EstimateCharacters(proc, content); got != tt.want {
+				t.Errorf("EstimateCharacters() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessorsKeepPrintfPlaceholders(t *testing.T) {
+	content := files.LanguageContent{
+		"greeting": "Hello, %1$s! You have %d new messages",
+		"percent":  "%d%%",
+	}
+	expected := files.LanguageContent{
+		"greeting": "[fr] Hello, %1$s[fr] ! You have %d[fr]  new messages",
+		"percent":  "%d%%",
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			var sent []string
+			mockTranslator := &MockTranslator{
+				MockTranslate: func(text, from, to string) (string, error) {
+					sent = append(sent, text)
+					return fmt.Sprintf("[%s] %s", to, text), nil
+				},
+			}
+
+			proc, err := processor.CreateProcessor(translationType, mockTranslator)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+
+			result, err := proc.Execute(context.Background(), content, "en", "fr", make(files.LanguageContent))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			compareMaps(t, result, expected)
+
+			for _, text := range sent {
+				if strings.Contains(text, "%") {
+					t.Errorf("Translate() received a placeholder in %q", text)
+				}
+			}
+		})
+	}
+}
+
+// describingTranslator records the descriptions it receives with each text
+type describingTranslator struct {
+	MockTranslator
+	descriptions map[string]string
+}
+
+// TranslateWithDescription records the description and translates like the mock
+func (d *describingTranslator) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
+	d.descriptions[text] = description
+	return d.Translate(ctx, text, from, to)
+}
+
+func TestProcessorsUseARBMetadata(t *testing.T) {
+	content := files.LanguageContent{
+		"greeting": "Hello",
+		"@greeting": map[string]interface{}{
+			"description": "Greeting on the home screen",
+		},
+		"welcome": "Welcome {name}",
+		"@welcome": map[string]interface{}{
+			"placeholders": map[string]interface{}{
+				"name": map[string]interface{}{"type": "String"},
+			},
+		},
+	}
+
+	// The AST processor never sends placeholders to the translator
+	wantWelcome := map[string]string{
+		"simple-json": "Welcome {name}",
+		"ast-json":    "[fr] Welcome {name}",
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			mockTranslator := &describingTranslator{
+				MockTranslator: MockTranslator{
+					MockTranslate: func(text, from, to string) (string, error) {
+						// Drop the placeholder like a careless translator would
+						return "[" + to + "] " + strings.ReplaceAll(text, "{name}", "{nom}"), nil
+					},
+				},
+				descriptions: make(map[string]string),
+			}
+
+			proc, err := processor.CreateProcessor(translationType, mockTranslator)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+
+			result, err := proc.Execute(context.Background(), content, "en", "fr", make(files.LanguageContent))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got := mockTranslator.descriptions["Hello"]; got != "Greeting on the home screen" {
+				t.Errorf("TranslateWithDescription() received description %q", got)
+			}
+			if result["greeting"] != "[fr] Hello" {
+				t.Errorf("Execute() greeting = %v", result["greeting"])
+			}
+			// Translations losing a declared placeholder keep the source
+			if result["welcome"] != wantWelcome[translationType] {
+				t.Errorf("Execute() welcome = %v, want %v", result["welcome"], wantWelcome[translationType])
+			}
+		})
+	}
+}
+
+func TestProcessorsTranslateArrays(t *testing.T) {
+	content := files.LanguageContent{
+		"steps": []interface{}{"Sign up", "Verify", 3},
+		"features": []interface{}{
+			map[string]interface{}{"title": "Fast", "tags": []interface{}{"Speed"}},
+			map[string]interface{}{"title": "Safe"},
+		},
+	}
+	// Previous translations are aligned by index, like untranslated strings kept on purpose
+	previous := files.LanguageContent{
+		"steps": []interface{}{"Sign up"},
+	}
+	expected := files.LanguageContent{
+		"steps": []interface{}{"Sign up", "[fr] Verify", 3},
+		"features": []interface{}{
+			map[string]interface{}{"title": "[fr] Fast", "tags": []interface{}{"[fr] Speed"}},
+			map[string]interface{}{"title": "[fr] Safe"},
+		},
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			proc, err := processor.CreateProcessor(translationType, createMockTranslator())
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			result, err := proc.Execute(context.Background(), content, "en", "fr", previous)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !reflect.DeepEqual(result["steps"], expected["steps"]) {
+				t.Errorf("steps = %v, want %v", result["steps"], expected["steps"])
+			}
+			features, ok := result["features"].([]interface{})
+			if !ok || len(features) != 2 {
+				t.Fatalf("features = %v, want an array of 2 items", result["features"])
+			}
+			for i, feature := range features {
+				got, _ := files.AsContent(feature)
+				want, _ := files.AsContent(expected["features"].([]interface{})[i])
+				compareMaps(t, got, want)
+			}
+			if got, want := processor.EstimateCharacters(proc, content), len("Sign up")+len("Verify")+len("Fast")+len("Speed")+len("Safe"); got != want {
+				t.Errorf("EstimateCharacters() = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+// messageTranslator records the whole messages it receives
+type messageTranslator struct {
+	mu       sync.Mutex
+	messages []translator.Message
+}
+
+func (m *messageTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return "", fmt.Errorf("Translate should not be called")
+}
+
+func (m *messageTranslator) TranslateMessage(ctx context.Context, message translator.Message, from, to string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return "[" + to + "] " + message.Text, nil
+}
+
+func TestProcessorsSendWholeMessages(t *testing.T) {
+	content := files.LanguageContent{
+		"home": map[string]interface{}{
+			"welcome": "Hello {name}, you have %d messages",
+		},
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			trans := &messageTranslator{}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			want := []translator.Message{{Key: "home.welcome", Text: "Hello {name}, you have %d messages"}}
+			if !reflect.DeepEqual(trans.messages, want) {
+				t.Errorf("Translator received %v, want %v", trans.messages, want)
+			}
+			home, _ := files.AsContent(result["home"])
+			if got := home["welcome"]; got != "[fr] Hello {name}, you have %d messages" {
+				t.Errorf("welcome = %v", got)
+			}
+		})
+	}
+}
+
+// batchTranslator records the batches it receives and fails on single texts
+type batchTranslator struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (b *batchTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return "", fmt.Errorf("Translate should not be called for '%s'", text)
+}
+
+func (b *batchTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.batches = append(b.batches, texts)
+	if b.err != nil {
+		return nil, b.err
+	}
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translations[i] = "[" + to + "] " + text
+	}
+	return translations, nil
+}
+
+func TestProcessorsTranslateInBatches(t *testing.T) {
+	content := files.LanguageContent{
+		"count":     "{count, plural, one {# file} other {# files}}",
+		"printf":    "Saved %d files",
+		"unchanged": "Kept",
+		"nested":    map[string]interface{}{"steps": []interface{}{"First", "Second"}},
+	}
+	for i := 0; i < 60; i++ {
+		content[fmt.Sprintf("key%d", i)] = fmt.Sprintf("Value %d", i)
+	}
+	previous := files.LanguageContent{"unchanged": "Kept"}
+
+	want := map[string]files.LanguageContent{
+		"simple-json": {
+			"count":  "[fr] {count, plural, one {# file} other {# files}}",
+			"printf": "[fr] Saved %d[fr]  files",
+		},
+		"ast-json": {
+			"count":  "{count, plural, one {#[fr]  file} other {#[fr]  files} }",
+			"printf": "[fr] Saved %d[fr]  files",
+		},
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			trans := &batchTranslator{}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			result, err := proc.Execute(context.Background(), content, "en", "fr", previous)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			// 65 texts fit in two batches of at most 50
+			if len(trans.batches) != 2 || len(trans.batches[0]) != 50 {
+				t.Errorf("Translator received %d batches, want 2 with the first full", len(trans.batches))
+			}
+			for _, batch := range trans.batches {
+				for _, text := range batch {
+					if text == "Kept" {
+						t.Errorf("Unchanged text %q was sent to the translator", text)
+					}
+				}
+			}
+
+			if result["key42"] != "[fr] Value 42" || result["unchanged"] != "Kept" {
+				t.Errorf("Execute() key42 = %v, unchanged = %v", result["key42"], result["unchanged"])
+			}
+			nested, _ := files.AsContent(result["nested"])
+			if !reflect.DeepEqual(nested["steps"], []interface{}{"[fr] First", "[fr] Second"}) {
+				t.Errorf("Execute() steps = %v", nested["steps"])
+			}
+			for key, value := range want[translationType] {
+				if result[key] != value {
+					t.Errorf("Execute() %s = %q, want %q", key, result[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessorsKeepSourceOfFailedBatches(t *testing.T) {
+	content := files.LanguageContent{"greeting": "Hello", "farewell": "Goodbye"}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			trans := &batchTranslator{err: fmt.Errorf("quota exceeded")}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if len(trans.batches) != 1 {
+				t.Errorf("Translator received %d batches, want 1", len(trans.batches))
+			}
+			if !reflect.DeepEqual(result, content) {
+				t.Errorf("Execute() = %v, want the source strings", result)
+			}
+		})
+	}
+}
+
+// describingBatchTranslator records the batches it receives per description
+// and fails on single texts
+type describingBatchTranslator struct {
+	batchTranslator
+	described map[string][][]string
+}
+
+func (d *describingBatchTranslator) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
+	return "", fmt.Errorf("TranslateWithDescription should not be called for '%s'", text)
+}
+
+func (d *describingBatchTranslator) TranslateBatchWithDescription(ctx context.Context, texts []string, description, from, to string) ([]string, error) {
+	d.mu.Lock()
+	d.described[description] = append(d.described[description], texts)
+	d.mu.Unlock()
+	return d.TranslateBatch(ctx, texts, from, to)
+}
+
+func TestProcessorsBatchDescribedStrings(t *testing.T) {
+	content := files.LanguageContent{
+		"title":     "Settings",
+		"@title":    map[string]interface{}{"description": "Screen title"},
+		"subtitle":  "Account",
+		"@subtitle": map[string]interface{}{"description": "Screen title"},
+		"save":      "Save",
+		"@save":     map[string]interface{}{"description": "Button label"},
+		"plain":     "Hello",
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			trans := &describingBatchTranslator{described: make(map[string][][]string)}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+			result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			want := map[string][][]string{
+				"Button label": {{"Save"}},
+				"Screen title": {{"Account", "Settings"}},
+			}
+			if !reflect.DeepEqual(trans.described, want) {
+				t.Errorf("Translator received described batches %v, want %v", trans.described, want)
+			}
+			// Two described batches and one without a description
+			if len(trans.batches) != 3 {
+				t.Errorf("Translator received %d batches, want 3", len(trans.batches))
+			}
+			for key, value := range map[string]string{"title": "[fr] Settings", "save": "[fr] Save", "plain": "[fr] Hello"} {
+				if result[key] != value {
+					t.Errorf("Execute() %s = %v, want %v", key, result[key], value)
+				}
+			}
+		})
+	}
+}
+
+// cancellingTranslator cancels the run after its first translation and
+// fails like an HTTP client once the run is cancelled
+type cancellingTranslator struct {
+	MockTranslator
+	cancel context.CancelFunc
+}
+
+func (c *cancellingTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	defer c.cancel()
+	return c.MockTranslator.Translate(ctx, text, from, to)
+}
+
+func TestProcessorsReturnPartialResultsWhenCancelled(t *testing.T) {
+	content := files.LanguageContent{
+		"@@locale": "en",
+		"greeting": "Hello",
+		"farewell": "Goodbye",
+		"nested":   map[string]interface{}{"title": "Title", "body": "Body"},
+	}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			trans := &cancellingTranslator{MockTranslator: *createMockTranslator(), cancel: cancel}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+
+			result, err := proc.Execute(ctx, content, "en", "fr", files.LanguageContent{})
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Execute() error = %v, want %v", err, context.Canceled)
+			}
+			if result["@@locale"] != "en" {
+				t.Errorf("Execute() dropped the metadata: %v", result)
+			}
+			translated := files.Flatten(result)
+			if len(translated) != 1 {
+				t.Fatalf("Execute() = %v, want a single finished translation", result)
+			}
+			for _, value := range translated {
+				if !strings.HasPrefix(value.(string), "[fr] ") {
+					t.Errorf("Execute() kept the source %v of an unfinished key", value)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessorsStopOnFatalErrors(t *testing.T) {
+	content := files.LanguageContent{"@@locale": "en"}
+	for i := 0; i < 20; i++ {
+		content[fmt.Sprintf("key%d", i)] = fmt.Sprintf("Value %d", i)
+	}
+	quotaErr := &translator.APIError{Provider: "Fake", StatusCode: 456, Message: "Quota exceeded", Kind: translator.ErrQuota}
+
+	for _, translationType := range []string{"simple-json", "ast-json"} {
+		t.Run(translationType, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			trans := &MockTranslator{MockTranslate: func(text, from, to string) (string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls++
+				return "", quotaErr
+			}}
+			proc, err := processor.CreateProcessor(translationType, trans)
+			if err != nil {
+				t.Fatalf("CreateProcessor() error = %v", err)
+			}
+
+			result, err := proc.Execute(context.Background(), content, "en", "fr", files.LanguageContent{})
+			if !errors.Is(err, translator.ErrQuota) {
+				t.Fatalf("Execute() error = %v, want %v", err, translator.ErrQuota)
+			}
+			if calls != 1 {
+				t.Errorf("Translator was called %d times, want once", calls)
+			}
+			if len(files.Flatten(result)) != 0 {
+				t.Errorf("Execute() = %v, want no translated keys", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Limits of a single Azure translation request, characters are counted
+// once per target language
+const (
+	azureMaxTexts = 1000
+	azureMaxChars = 50000
+)
+
+// tagRegex matches markup tags like <b> or </link>
+var tagRegex = regexp.MustCompile(`</?[A-Za-z][\w-]*(\s[^<>]*)?/?>`)
+
+// AzureTranslator implements the Translator interface using Microsoft Azure AI Translator
+type AzureTranslator struct {
+	endpoint string
+	apiKey   string
+	region   string
+	category string
+	// textType is "plain", "html", or "" to send texts with tags as html
+	textType string
+	client   *apiClient
+}
+
+func init() {
+	Register("azure", newAzureProvider)
+}
+
+// NewAzureTranslator creates an Azure translator from provider options:
+//
+//   - apiKey: resource key, defaults to AZURE_TRANSLATOR_KEY
+//   - region: region of the resource, defaults to AZURE_TRANSLATOR_REGION
+//   - category: custom translator category
+//   - textType: "plain" or "html", by default texts with tags are sent as html
+//   - endpoint: defaults to https://api.cognitive.microsofttranslator.com
+func NewAzureTranslator(options Options) (*AzureTranslator, error) {
+	t := &AzureTranslator{
+		endpoint: strings.TrimSuffix(options.String("endpoint"), "/"),
+		apiKey:   options.String("apiKey"),
+		region:   options.String("region"),
+		category: options.String("category"),
+		textType: options.String("textType"),
+	}
+	client, err := newAPIClient(options)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	if t.endpoint == "" {
+		t.endpoint = "https://api.cognitive.microsofttranslator.com"
+	}
+	if t.apiKey == "" {
+		t.apiKey = os.Getenv("AZURE_TRANSLATOR_KEY")
+	}
+	if t.region == "" {
+		t.region = os.Getenv("AZURE_TRANSLATOR_REGION")
+	}
+	if t.apiKey == "" {
+		return nil, fmt.Errorf("Azure provider needs an apiKey or the AZURE_TRANSLATOR_KEY environment variable")
+	}
+	if t.textType != "" && t.textType != "plain" && t.textType != "html" {
+		return nil, fmt.Errorf("Azure textType must be 'plain' or 'html', got '%s'", t.textType)
+	}
+	return t, nil
+}
+
+func newAzureProvider(options Options) (Translator, error) {
+	return NewAzureTranslator(options)
+}
+
+// Translate implements the Translator interface for Azure
+func (t *AzureTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	translations, err := t.TranslateBatch(ctx, []string{text}, from, to)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch translates texts into a single language, returning the
+// translations in the same order
+func (t *AzureTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	if from == to {
+		return texts, nil // No need to translate if source and target languages are the same
+	}
+	translations, err := t.translateLanguages(ctx, texts, from, []string{to})
+	if err != nil {
+		return nil, err
+	}
+	return translations[to], nil
+}
+
+// translateLanguages translates texts into every language of targets with
+// as few requests as possible, returning the translations of each language
+// in the same order as texts. The pipeline translates one language at a
+// time, so TranslateBatch passes a single target.
+func (t *AzureTranslator) translateLanguages(ctx context.Context, texts []string, from string, targets []string) (map[string][]string, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("Azure needs at least one target language")
+	}
+	result := make(map[string][]string, len(targets))
+	for _, to := range targets {
+		result[to] = make([]string, len(texts))
+	}
+
+	// Plain text and markup are sent in separate requests
+	groups := make(map[string][]int)
+	for i, text := range texts {
+		textType := t.textType
+		if textType == "" {
+			textType = "plain"
+			if tagRegex.MatchString(text) {
+				textType = "html"
+			}
+		}
+		groups[textType] = append(groups[textType], i)
+	}
+
+	for _, textType := range []string{"plain", "html"} {
+		indexes := groups[textType]
+		groupTexts := make([]string, len(indexes))
+		for i, index := range indexes {
+			groupTexts[i] = texts[index]
+		}
+
+		offset := 0
+		for _, batch := range chunk(groupTexts, azureMaxTexts, azureMaxChars/len(targets)) {
+			translations, err := t.translate(ctx, batch, from, targets, textType)
+			if err != nil {
+				return nil, err
+			}
+			for to, translated := range translations {
+				for i, text := range translated {
+					result[to][indexes[offset+i]] = text
+				}
+			}
+			offset += len(batch)
+		}
+	}
+
+	return result, nil
+}
+
+// translate sends a single request
+func (t *AzureTranslator) translate(ctx context.Context, texts []string, from string, targets []string, textType string) (map[string][]string, error) {
+	query := url.Values{}
+	query.Set("api-version", "3.0")
+	if from != "" {
+		query.Set("from", from)
+	}
+	for _, to := range targets {
+		query.Add("to", to)
+	}
+	query.Set("textType", textType)
+	if t.category != "" {
+		query.Set("category", t.category)
+	}
+
+	header := http.Header{}
+	header.Set("Ocp-Apim-Subscription-Key", t.apiKey)
+	if t.region != "" {
+		header.Set("Ocp-Apim-Subscription-Region", t.region)
+	}
+
+	request := make([]map[string]string, len(texts))
+	for i, text := range texts {
+		request[i] = map[string]string{"Text": text}
+	}
+
+	var response []struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := postJSON(ctx, t.client, "Azure", t.endpoint+"/translate?"+query.Encode(), header, request, &response); err != nil {
+		return nil, azureError(err)
+	}
+	if len(response) != len(texts) {
+		return nil, fmt.Errorf("Azure response contained %d results for %d texts", len(response), len(texts))
+	}
+
+	result := make(map[string][]string, len(targets))
+	for _, to := range targets {
+		result[to] = make([]string, len(texts))
+	}
+	for i, item := range response {
+		// Translations come in the order of the target languages
+		if len(item.Translations) != len(targets) {
+			return nil, fmt.Errorf("Azure response contained %d translations for %d languages", len(item.Translations), len(targets))
+		}
+		for j, translation := range item.Translations {
+			result[targets[j]][i] = translation.Text
+		}
+	}
+	return result, nil
+}
+
+// azureError sets the Kind of a failed Azure request from the error code of
+// its response. Azure answers 403 both for keys that are not allowed, with
+// code 403000, and for an exhausted free quota, with codes 403001 and up.
+func azureError(err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+	var body struct {
+		Error struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.Message), &body) == nil && body.Error.Code > 403000 && body.Error.Code < 404000 {
+		apiErr.Kind = ErrQuota
+	}
+	return err
+}
@@ -1,138 +1,318 @@
 package translator
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
-	"time"
+	"sync"
+)
+
+// Base URLs of the DeepL API Free and DeepL API Pro plans
+const (
+	DeeplFreeURL = "https://api-free.deepl.com"
+	DeeplProURL  = "https://api.deepl.com"
+)
+
+// Limits of a single DeepL translation request, which accepts 50 texts and
+// a 128 KiB body
+const (
+	deeplMaxTexts = 50
+	deeplMaxBody  = 128 * 1024
+	// deeplReservedBody is the part of the body kept for the parameters
+	// other than the texts, like the languages and the glossary
+	deeplReservedBody = 8 * 1024
 )
 
 // DeeplTranslator implements the Translator interface using DeepL API
 type DeeplTranslator struct {
-	apiKey string
-	client *http.Client
-	maxRetries int
-	initialBackoff time.Duration
+	apiKey  string
+	baseURL string
+	client  *apiClient
+
+	// Request options, sent only when set
+	formality          string
+	context            string
+	preserveFormatting string
+	splitSentences     string
+	tagHandling        string
+	ignoreTags         []string
+
+	// glossaryName names the glossaries used for translations, glossaries
+	// caches the glossaries of the account once listed. Listing them only
+	// fails translations when the glossary option named them explicitly.
+	glossaryName     string
+	glossaryRequired bool
+	glossaryListMu   sync.Mutex
+	glossaryMu       sync.Mutex
+	glossaries       []Glossary
 }
 
 type deeplResponse struct {
 	Translations []struct {
 		DetectedSourceLanguage string `json:"detected_source_language"`
-		Text                  string `json:"text"`
+		Text                   string `json:"text"`
 	} `json:"translations"`
 }
 
-// NewDeeplTranslator creates a new DeepL translator using environment variables
+func init() {
+	Register("deepl", newDeeplProvider)
+}
+
+// NewDeeplTranslator creates a new DeepL translator using environment
+// variables. DEEPL_BASE_URL overrides the endpoint of the key's plan.
 func NewDeeplTranslator() (*DeeplTranslator, error) {
 	apiKey := os.Getenv("DEEPL_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("DEEPL_API_KEY environment variable is not set")
 	}
+	return newDeeplTranslator(apiKey, os.Getenv("DEEPL_BASE_URL")), nil
+}
+
+// newDeeplProvider creates a DeepL translator from provider options:
+//
+//   - apiKey: defaults to DEEPL_API_KEY
+//   - baseURL: defaults to DEEPL_BASE_URL, or the API of the key's plan
+//   - glossary: name of the glossaries to use, defaults to "globify"
+//   - formality: "default", "more", "less", "prefer_more" or "prefer_less"
+//   - context: text sent as context with every string, before its description
+//   - preserveFormatting: keep the punctuation and casing of the source
+//   - splitSentences: "0", "1" or "nonewlines"
+//   - tagHandling: "xml" or "html", with ignoreTags listing tags whose content is kept
+//
+// Options for a single language are set in its override of the provider block.
+func newDeeplProvider(options Options) (Translator, error) {
+	apiKey := options.String("apiKey")
+	if apiKey == "" {
+		apiKey = os.Getenv("DEEPL_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("DeepL provider needs an apiKey or the DEEPL_API_KEY environment variable")
+	}
+	baseURL := options.String("baseURL")
+	if baseURL == "" {
+		baseURL = os.Getenv("DEEPL_BASE_URL")
+	}
+
+	t := newDeeplTranslator(apiKey, baseURL)
+	client, err := newAPIClient(options)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+	if glossary := options.String("glossary"); glossary != "" {
+		t.glossaryName = glossary
+		t.glossaryRequired = true
+	}
+
+	t.formality = options.String("formality")
+	switch t.formality {
+	case "", "default", "more", "less", "prefer_more", "prefer_less":
+	default:
+		return nil, fmt.Errorf("DeepL formality must be 'default', 'more', 'less', 'prefer_more' or 'prefer_less', got '%s'", t.formality)
+	}
+
+	t.context = options.String("context")
+
+	if _, ok := options["preserveFormatting"]; ok {
+		preserve, err := options.Bool("preserveFormatting", false)
+		if err != nil {
+			return nil, err
+		}
+		t.preserveFormatting = "0"
+		if preserve {
+			t.preserveFormatting = "1"
+		}
+	}
+
+	t.splitSentences = options.String("splitSentences")
+	switch t.splitSentences {
+	case "", "0", "1", "nonewlines":
+	default:
+		return nil, fmt.Errorf("DeepL splitSentences must be '0', '1' or 'nonewlines', got '%s'", t.splitSentences)
+	}
+
+	t.tagHandling = options.String("tagHandling")
+	if t.tagHandling != "" && t.tagHandling != "xml" && t.tagHandling != "html" {
+		return nil, fmt.Errorf("DeepL tagHandling must be 'xml' or 'html', got '%s'", t.tagHandling)
+	}
+	ignoreTags, err := options.Strings("ignoreTags")
+	if err != nil {
+		return nil, err
+	}
+	if len(ignoreTags) > 0 && t.tagHandling == "" {
+		return nil, fmt.Errorf("DeepL ignoreTags needs tagHandling")
+	}
+	t.ignoreTags = ignoreTags
+
+	return t, nil
+}
+
+// newDeeplTranslator creates a DeepL translator, using the API of the key's
+// plan when baseURL is empty
+func newDeeplTranslator(apiKey, baseURL string) *DeeplTranslator {
+	if baseURL == "" {
+		baseURL = DeeplBaseURL(apiKey)
+	}
 	return &DeeplTranslator{
-		apiKey: apiKey,
-		client: &http.Client{},
-		maxRetries: 5,                // Maximum number of retry attempts
-		initialBackoff: time.Second,  // Start with 1 second delay before first retry
-	}, nil
+		apiKey:       apiKey,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		client:       &apiClient{http: &http.Client{Timeout: DefaultTimeout}, maxRetries: DefaultRetries},
+		glossaryName: DefaultGlossaryName,
+	}
+}
+
+// DeeplBaseURL returns the API URL of the plan of apiKey. Keys of the
+// DeepL API Free plan end with ":fx".
+func DeeplBaseURL(apiKey string) string {
+	if strings.HasSuffix(apiKey, ":fx") {
+		return DeeplFreeURL
+	}
+	return DeeplProURL
+}
+
+// Usage implements the UsageReporter interface with the character count
+// and limit of the current billing period
+func (t *DeeplTranslator) Usage(ctx context.Context) (*Usage, error) {
+	var response struct {
+		CharacterCount int64 `json:"character_count"`
+		CharacterLimit int64 `json:"character_limit"`
+	}
+	if err := sendJSON(ctx, t.client, "DeepL", http.MethodGet, t.baseURL+"/v2/usage", t.header(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &Usage{Provider: "DeepL", Characters: response.CharacterCount, Limit: response.CharacterLimit}, nil
+}
+
+// BaseURL returns the URL of the DeepL API the translator sends requests to
+func (t *DeeplTranslator) BaseURL() string {
+	return t.baseURL
 }
 
 // Translate implements the Translator interface for DeepL
-func (t *DeeplTranslator) Translate(text, from, to string) (string, error) {
+func (t *DeeplTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return t.TranslateWithDescription(ctx, text, "", from, to)
+}
+
+// TranslateWithDescription implements the DescriptionTranslator interface,
+// sending the description as the context of the text
+func (t *DeeplTranslator) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
 	if text == "" {
 		return "", nil
 	}
-	
+
 	if from == to {
 		return text, nil // No need to translate if source and target languages are the same
 	}
 
-	apiURL := "https://api-free.deepl.com/v2/translate"
-	
-	data := url.Values{}
-	data.Set("text", text)
-	data.Set("target_lang", to)
-	if from != "" {
-		data.Set("source_lang", from)
+	translations, err := t.translate(ctx, []string{text}, description, from, to)
+	if err != nil {
+		return "", err
 	}
+	return translations[0], nil
+}
 
-	// Initialize variables for retry mechanism
-	var (
-		resp *http.Response
-		respErr error
-		body []byte
-		attempts int
-		backoff = t.initialBackoff
-	)
-
-	// Random source for jitter
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	// Try the request with exponential backoff and jitter
-	for attempts = 0; attempts <= t.maxRetries; attempts++ {
-		if attempts > 0 {
-			// Calculate backoff with jitter for retry
-			jitter := time.Duration(r.Float64() * float64(backoff) * 0.3) // 30% jitter
-			sleepTime := backoff + jitter
-			
-			// Log the retry attempt
-			fmt.Printf("Rate limit exceeded. Retrying in %.2f seconds (attempt %d/%d)...\n", 
-				sleepTime.Seconds(), attempts, t.maxRetries)
-			
-			time.Sleep(sleepTime)
-			
-			// Exponential backoff for next iteration
-			backoff = time.Duration(float64(backoff) * 2)
-		}
+// TranslateBatch implements the BatchTranslator interface, sending up to 50
+// texts per request
+func (t *DeeplTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	return t.TranslateBatchWithDescription(ctx, texts, "", from, to)
+}
+
+// TranslateBatchWithDescription implements the DescriptionBatchTranslator
+// interface, sending the description as the context of every request
+func (t *DeeplTranslator) TranslateBatchWithDescription(ctx context.Context, texts []string, description, from, to string) ([]string, error) {
+	if from == to {
+		return texts, nil // No need to translate if source and target languages are the same
+	}
 
-		// Create a new request for each attempt
-		req, err := http.NewRequest("POST", apiURL, strings.NewReader(data.Encode()))
+	// Texts are sized as the URL encoded parameters of the form body, where
+	// non-ASCII text takes three times its UTF-8 length
+	maxSize := deeplMaxBody - deeplReservedBody - len(url.QueryEscape(t.context+"\n"+description))
+	translations := make([]string, 0, len(texts))
+	for _, batch := range chunkBySize(texts, deeplMaxTexts, maxSize, deeplTextSize) {
+		translated, err := t.translate(ctx, batch, description, from, to)
 		if err != nil {
-			return "", fmt.Errorf("failed to create DeepL request: %w", err)
+			return nil, err
 		}
+		translations = append(translations, translated...)
+	}
+	return translations, nil
+}
 
-		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Add("Authorization", "DeepL-Auth-Key "+t.apiKey)
+// deeplTextSize returns the size of the text parameter of text in a form body
+func deeplTextSize(text string) int {
+	return len("&text=") + len(url.QueryEscape(text))
+}
 
-		resp, respErr = t.client.Do(req)
-		if respErr != nil {
-			// Network errors are retryable
-			continue
-		}
+// translate sends a single request translating texts, with description as their context
+func (t *DeeplTranslator) translate(ctx context.Context, texts []string, description, from, to string) ([]string, error) {
+	apiURL := t.baseURL + "/v2/translate"
 
-		body, err = io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return "", fmt.Errorf("failed to read DeepL response body: %w", err)
-		}
-
-		// If not a rate limit error, break out of the retry loop
-		if resp.StatusCode != http.StatusTooManyRequests {
-			break
-		}
+	data := url.Values{}
+	for _, text := range texts {
+		data.Add("text", text)
+	}
+	data.Set("target_lang", to)
+	if from != "" {
+		data.Set("source_lang", from)
+	}
+	if textContext := strings.TrimSpace(t.context + "\n" + description); textContext != "" {
+		data.Set("context", textContext)
+	}
+	if t.formality != "" {
+		data.Set("formality", t.formality)
+	}
+	if t.preserveFormatting != "" {
+		data.Set("preserve_formatting", t.preserveFormatting)
+	}
+	if t.splitSentences != "" {
+		data.Set("split_sentences", t.splitSentences)
+	}
+	if t.tagHandling != "" {
+		data.Set("tag_handling", t.tagHandling)
+	}
+	if len(t.ignoreTags) > 0 {
+		data.Set("ignore_tags", strings.Join(t.ignoreTags, ","))
 	}
 
-	// If we exhausted all retries and still getting errors
-	if attempts > t.maxRetries {
-		return "", fmt.Errorf("exceeded maximum retries (%d) for DeepL API", t.maxRetries)
+	// Enforce the terminology of the glossary of the language pair, if any
+	glossaryID, err := t.glossaryID(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if glossaryID != "" {
+		data.Set("glossary_id", glossaryID)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("DeepL API request failed with status %d: %s", resp.StatusCode, string(body))
+	// Rate limits and temporary failures are retried, other errors returned as they are
+	body, err := t.client.do(ctx, "DeepL", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(data.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Add("Authorization", "DeepL-Auth-Key "+t.apiKey)
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	var result deeplResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return "", fmt.Errorf("failed to parse DeepL response JSON: %w", err)
+		return nil, fmt.Errorf("failed to parse DeepL response JSON: %w", err)
 	}
 
-	if len(result.Translations) == 0 {
-		return "", fmt.Errorf("DeepL response contained no translations")
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("DeepL response contained %d translations for %d texts", len(result.Translations), len(texts))
 	}
 
-	return result.Translations[0].Text, nil
-}
\ No newline at end of file
+	translations := make([]string, len(result.Translations))
+	for i, translation := range result.Translations {
+		translations[i] = translation.Text
+	}
+	return translations, nil
+}
@@ -0,0 +1,225 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultGlossaryName is the name of the DeepL glossaries globify manages
+// and uses, unless the glossary option of the provider names another one
+const DefaultGlossaryName = "globify"
+
+// Glossary is a DeepL glossary of a language pair
+type Glossary struct {
+	ID           string `json:"glossary_id"`
+	Name         string `json:"name"`
+	Ready        bool   `json:"ready"`
+	SourceLang   string `json:"source_lang"`
+	TargetLang   string `json:"target_lang"`
+	CreationTime string `json:"creation_time"`
+	EntryCount   int    `json:"entry_count"`
+}
+
+// GlossaryEntry is a term and the translation DeepL must use for it
+type GlossaryEntry struct {
+	Source string
+	Target string
+}
+
+// GlossaryName returns the name of the glossaries the translator uses
+func (t *DeeplTranslator) GlossaryName() string {
+	return t.glossaryName
+}
+
+// ListGlossaries returns every glossary of the DeepL account
+func (t *DeeplTranslator) ListGlossaries(ctx context.Context) ([]Glossary, error) {
+	var response struct {
+		Glossaries []Glossary `json:"glossaries"`
+	}
+	if err := sendJSON(ctx, t.client, "DeepL", http.MethodGet, t.baseURL+"/v2/glossaries", t.header(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Glossaries, nil
+}
+
+// CreateGlossary creates a glossary of entries for translations from one
+// language to another. DeepL glossaries cannot be modified, so updating a
+// glossary means creating a new one and deleting the old one.
+func (t *DeeplTranslator) CreateGlossary(ctx context.Context, name, from, to string, entries []GlossaryEntry) (*Glossary, error) {
+	var tsv strings.Builder
+	for _, entry := range entries {
+		tsv.WriteString(entry.Source + "\t" + entry.Target + "\n")
+	}
+
+	request := map[string]string{
+		"name":           name,
+		"source_lang":    glossaryLanguage(from),
+		"target_lang":    glossaryLanguage(to),
+		"entries":        tsv.String(),
+		"entries_format": "tsv",
+	}
+
+	var glossary Glossary
+	if err := postJSON(ctx, t.client, "DeepL", t.baseURL+"/v2/glossaries", t.header(), request, &glossary); err != nil {
+		return nil, err
+	}
+	t.forgetGlossaries()
+	return &glossary, nil
+}
+
+// DeleteGlossary deletes the glossary with the given id
+func (t *DeeplTranslator) DeleteGlossary(ctx context.Context, id string) error {
+	if err := sendJSON(ctx, t.client, "DeepL", http.MethodDelete, t.baseURL+"/v2/glossaries/"+url.PathEscape(id), t.header(), nil, nil); err != nil {
+		return err
+	}
+	t.forgetGlossaries()
+	return nil
+}
+
+// FindGlossaries returns the glossaries named name for translations from one language to another
+func FindGlossaries(glossaries []Glossary, name, from, to string) []Glossary {
+	var found []Glossary
+	for _, glossary := range glossaries {
+		if glossary.Name == name &&
+			glossary.SourceLang == glossaryLanguage(from) &&
+			glossary.TargetLang == glossaryLanguage(to) {
+			found = append(found, glossary)
+		}
+	}
+	return found
+}
+
+// glossaryID returns the id of the glossary of the translator for
+// translations from one language to another, or "" when there is none.
+// The glossaries are listed once and kept until one is created or deleted.
+// When listing fails, translations go on without a glossary, unless the
+// glossary option named one.
+func (t *DeeplTranslator) glossaryID(ctx context.Context, from, to string) (string, error) {
+	if from == "" {
+		return "", nil // DeepL only uses glossaries with a source language
+	}
+
+	glossaries := t.cachedGlossaries()
+	if glossaries == nil {
+		// Concurrent translations wait for a single listing
+		t.glossaryListMu.Lock()
+		defer t.glossaryListMu.Unlock()
+
+		if glossaries = t.cachedGlossaries(); glossaries == nil {
+			listed, err := t.ListGlossaries(ctx)
+			if err != nil {
+				if t.glossaryRequired || ctx.Err() != nil {
+					return "", fmt.Errorf("failed to list DeepL glossaries: %w", err)
+				}
+				log.Printf("Warning: failed to list DeepL glossaries, translating without them: %v", err)
+			}
+			glossaries = listed
+			if glossaries == nil {
+				glossaries = []Glossary{}
+			}
+			t.glossaryMu.Lock()
+			t.glossaries = glossaries
+			t.glossaryMu.Unlock()
+		}
+	}
+
+	for _, glossary := range FindGlossaries(glossaries, t.glossaryName, from, to) {
+		if glossary.Ready {
+			return glossary.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// cachedGlossaries returns the glossaries listed last, or nil when they are not listed yet
+func (t *DeeplTranslator) cachedGlossaries() []Glossary {
+	t.glossaryMu.Lock()
+	defer t.glossaryMu.Unlock()
+	return t.glossaries
+}
+
+// forgetGlossaries makes the next translation list the glossaries again
+func (t *DeeplTranslator) forgetGlossaries() {
+	t.glossaryMu.Lock()
+	t.glossaries = nil
+	t.glossaryMu.Unlock()
+}
+
+// header returns the authorization header of DeepL requests
+func (t *DeeplTranslator) header() http.Header {
+	header := http.Header{}
+	header.Set("Authorization", "DeepL-Auth-Key "+t.apiKey)
+	return header
+}
+
+// glossaryLanguage returns the language code DeepL glossaries use for lang,
+// like "en" for "EN-GB", since glossaries apply to every variant of a language
+func glossaryLanguage(lang string) string {
+	lang, _, _ = strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	return strings.ToLower(lang)
+}
+
+// ReadGlossaryFile reads the entries of a glossary file, with one term and
+// its translation per line. Files ending with .tsv are tab separated and
+// other files comma separated, with CSV quoting. Lines starting with # are
+// ignored.
+func ReadGlossaryFile(path string) ([]GlossaryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glossary file: %w", err)
+	}
+
+	var records [][]string
+	if strings.EqualFold(filepath.Ext(path), ".tsv") {
+		// Quotes are part of the terms of tab separated files
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			record := strings.Split(line, "\t")
+			if len(record) != 2 {
+				return nil, fmt.Errorf("failed to parse glossary file %s: line %d has %d fields instead of 2", path, i+1, len(record))
+			}
+			records = append(records, record)
+		}
+	} else {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.Comment = '#'
+		reader.FieldsPerRecord = 2
+		reader.TrimLeadingSpace = true
+		records, err = reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse glossary file %s: %w", path, err)
+		}
+	}
+
+	entries := make([]GlossaryEntry, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for i, record := range records {
+		entry := GlossaryEntry{Source: strings.TrimSpace(record[0]), Target: strings.TrimSpace(record[1])}
+		if entry.Source == "" || entry.Target == "" {
+			return nil, fmt.Errorf("glossary file %s has an empty term on entry %d", path, i+1)
+		}
+		if strings.ContainsAny(entry.Source+entry.Target, "\t\n\r") {
+			return nil, fmt.Errorf("glossary file %s has a term with a tab or line break on entry %d", path, i+1)
+		}
+		if seen[entry.Source] {
+			return nil, fmt.Errorf("glossary file %s defines '%s' twice", path, entry.Source)
+		}
+		seen[entry.Source] = true
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("glossary file %s has no entries", path)
+	}
+	return entries, nil
+}
@@ -0,0 +1,115 @@
+package translator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of provider failures, matched with errors.Is
+var (
+	// ErrAuth means the provider rejected the credentials, like an invalid API key
+	ErrAuth = errors.New("authentication failed")
+	// ErrQuota means the character quota of the account is used up
+	ErrQuota = errors.New("quota exceeded")
+	// ErrRateLimit means the provider received too many requests
+	ErrRateLimit = errors.New("rate limit exceeded")
+	// ErrTransient means the provider or the network failed temporarily
+	ErrTransient = errors.New("temporary failure")
+	// ErrBadRequest means the provider rejected the request itself, like a
+	// text that is too long or an unsupported language
+	ErrBadRequest = errors.New("bad request")
+)
+
+// statusQuotaExceeded is the status DeepL answers with once the quota is used up
+const statusQuotaExceeded = 456
+
+// APIError is the error of a failed provider request
+type APIError struct {
+	Provider string
+	// StatusCode is the HTTP status of the response, 0 when none was received
+	StatusCode int
+	// Message is the body of the response
+	Message string
+	// RetryAfter is the wait the provider asked for before the next request,
+	// 0 when it asked for none or for an immediate retry
+	RetryAfter time.Duration
+	// Kind is one of ErrAuth, ErrQuota, ErrRateLimit, ErrTransient or ErrBadRequest
+	Kind error
+	// Err is the network error of requests without a response
+	Err error
+
+	// hasRetryAfter is set when the response had a Retry-After header
+	hasRetryAfter bool
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%s API request failed: %v", e.Provider, e.Err)
+	}
+	return fmt.Sprintf("%s API request failed with status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// Unwrap returns the kind of the error and its network error
+func (e *APIError) Unwrap() []error {
+	var errs []error
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// IsFatal reports whether err fails every other request to the provider
+// too, like a rejected API key or a used up quota, so translating the
+// remaining strings is pointless
+func IsFatal(err error) bool {
+	return errors.Is(err, ErrAuth) || errors.Is(err, ErrQuota)
+}
+
+// isRetryable reports whether a request failing with err may succeed when sent again
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrRateLimit) || errors.Is(err, ErrTransient)
+}
+
+// statusError returns the error of a response with a status other than 2xx
+func statusError(provider string, resp *http.Response, body []byte) *APIError {
+	err := &APIError{
+		Provider:   provider,
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+	}
+	err.RetryAfter, err.hasRetryAfter = retryAfter(resp.Header.Get("Retry-After"))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err.Kind = ErrAuth
+	case resp.StatusCode == statusQuotaExceeded:
+		err.Kind = ErrQuota
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 529: // DeepL answers 529 when overloaded
+		err.Kind = ErrRateLimit
+	case resp.StatusCode >= 500:
+		err.Kind = ErrTransient
+	case resp.StatusCode >= 400:
+		err.Kind = ErrBadRequest
+	}
+	return err
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as a date,
+// and reports whether it holds a valid wait
+func retryAfter(header string) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,253 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Google Cloud Translation API versions
+const (
+	GoogleV2 = "v2"
+	GoogleV3 = "v3"
+)
+
+// Limits of a single Google translation request
+const (
+	googleV2MaxTexts = 128
+	googleV3MaxTexts = 1024
+	googleMaxChars   = 30000
+)
+
+// GoogleTranslator implements the Translator interface using the Google
+// Cloud Translation API, either the v2 "Basic" API with an API key or a
+// service account, or the v3 "Advanced" API with a service account
+type GoogleTranslator struct {
+	version  string
+	baseURL  string
+	apiKey   string
+	tokens   *googleTokenSource
+	project  string
+	location string
+	// format is "text" or "html"
+	format string
+	client *apiClient
+}
+
+func init() {
+	Register("google", newGoogleProvider)
+}
+
+// NewGoogleTranslator creates a Google translator from provider options:
+//
+//   - apiKey: API key of the v2 API, defaults to GOOGLE_API_KEY
+//   - credentials: service account key file, defaults to GOOGLE_APPLICATION_CREDENTIALS
+//   - version: "v2" or "v3", defaults to v2 with an API key and v3 otherwise
+//   - project: project of the v3 API, defaults to the one of the service account
+//   - location: location of the v3 API, defaults to "global"
+//   - format: "text" or "html", defaults to "text"
+//   - baseURL: defaults to https://translation.googleapis.com
+func NewGoogleTranslator(options Options) (*GoogleTranslator, error) {
+	t := &GoogleTranslator{
+		version:  options.String("version"),
+		baseURL:  strings.TrimSuffix(options.String("baseURL"), "/"),
+		apiKey:   options.String("apiKey"),
+		project:  options.String("project"),
+		location: options.String("location"),
+		format:   options.String("format"),
+	}
+	client, err := newAPIClient(options)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	if t.baseURL == "" {
+		t.baseURL = "https://translation.googleapis.com"
+	}
+	if t.location == "" {
+		t.location = "global"
+	}
+	switch t.format {
+	case "":
+		t.format = "text"
+	case "text", "html":
+	default:
+		return nil, fmt.Errorf("Google format must be 'text' or 'html', got '%s'", t.format)
+	}
+
+	credentials := options.String("credentials")
+	if t.apiKey == "" && credentials == "" {
+		t.apiKey = os.Getenv("GOOGLE_API_KEY")
+		credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if credentials != "" {
+		tokens, err := readGoogleServiceAccount(credentials, t.client)
+		if err != nil {
+			return nil, err
+		}
+		t.tokens = tokens
+		if t.project == "" {
+			t.project = tokens.account.ProjectID
+		}
+	}
+	if t.apiKey == "" && t.tokens == nil {
+		return nil, fmt.Errorf("Google provider needs an apiKey or service account credentials, or the GOOGLE_API_KEY or GOOGLE_APPLICATION_CREDENTIALS environment variable")
+	}
+
+	if t.version == "" {
+		t.version = GoogleV3
+		if t.apiKey != "" {
+			t.version = GoogleV2
+		}
+	}
+	switch t.version {
+	case GoogleV2:
+	case GoogleV3:
+		if t.tokens == nil {
+			return nil, fmt.Errorf("Google v3 API needs service account credentials")
+		}
+		if t.project == "" {
+			return nil, fmt.Errorf("Google v3 API needs a project")
+		}
+	default:
+		return nil, fmt.Errorf("Google version must be '%s' or '%s', got '%s'", GoogleV2, GoogleV3, t.version)
+	}
+
+	return t, nil
+}
+
+func newGoogleProvider(options Options) (Translator, error) {
+	return NewGoogleTranslator(options)
+}
+
+// Translate implements the Translator interface for Google
+func (t *GoogleTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	translations, err := t.TranslateBatch(ctx, []string{text}, from, to)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch translates texts with as few requests as the API limits
+// allow, returning the translations in the same order
+func (t *GoogleTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	if from == to {
+		return texts, nil // No need to translate if source and target languages are the same
+	}
+
+	maxTexts := googleV2MaxTexts
+	if t.version == GoogleV3 {
+		maxTexts = googleV3MaxTexts
+	}
+
+	translations := make([]string, 0, len(texts))
+	for _, texts := range chunk(texts, maxTexts, googleMaxChars) {
+		var translated []string
+		var err error
+		if t.version == GoogleV3 {
+			translated, err = t.translateV3(ctx, texts, from, to)
+		} else {
+			translated, err = t.translateV2(ctx, texts, from, to)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(translated) != len(texts) {
+			return nil, fmt.Errorf("Google response contained %d translations for %d texts", len(translated), len(texts))
+		}
+		translations = append(translations, translated...)
+	}
+	return translations, nil
+}
+
+// translateV2 sends a single request to the v2 API
+func (t *GoogleTranslator) translateV2(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	endpoint := t.baseURL + "/language/translate/v2"
+	header := http.Header{}
+	if t.apiKey != "" {
+		// The key stays out of the URL, which network errors and logs show
+		header.Set("X-Goog-Api-Key", t.apiKey)
+	} else if err := t.authorize(ctx, header); err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{
+		"q":      texts,
+		"target": to,
+		"format": t.format,
+	}
+	if from != "" {
+		request["source"] = from
+	}
+
+	var response struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, t.client, "Google", endpoint, header, request, &response); err != nil {
+		return nil, err
+	}
+
+	translations := make([]string, len(response.Data.Translations))
+	for i, translation := range response.Data.Translations {
+		translations[i] = translation.TranslatedText
+	}
+	return translations, nil
+}
+
+// translateV3 sends a single request to the v3 API
+func (t *GoogleTranslator) translateV3(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/v3/projects/%s/locations/%s:translateText", t.baseURL, url.PathEscape(t.project), url.PathEscape(t.location))
+	header := http.Header{}
+	if err := t.authorize(ctx, header); err != nil {
+		return nil, err
+	}
+
+	request := map[string]interface{}{
+		"contents":           texts,
+		"targetLanguageCode": to,
+		"mimeType":           "text/plain",
+	}
+	if t.format == "html" {
+		request["mimeType"] = "text/html"
+	}
+	if from != "" {
+		request["sourceLanguageCode"] = from
+	}
+
+	var response struct {
+		Translations []struct {
+			TranslatedText string `json:"translatedText"`
+		} `json:"translations"`
+	}
+	if err := postJSON(ctx, t.client, "Google", endpoint, header, request, &response); err != nil {
+		return nil, err
+	}
+
+	translations := make([]string, len(response.Translations))
+	for i, translation := range response.Translations {
+		translations[i] = translation.TranslatedText
+	}
+	return translations, nil
+}
+
+// authorize adds the access token of the service account to header
+func (t *GoogleTranslator) authorize(ctx context.Context, header http.Header) error {
+	token, err := t.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	header.Set("Authorization", "Bearer "+token)
+	return nil
+}
@@ -0,0 +1,170 @@
+package translator
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// googleScope is the OAuth scope of the Cloud Translation API
+const googleScope = "https://www.googleapis.com/auth/cloud-translation"
+
+// googleServiceAccount is the part of a service account key file used to get access tokens
+type googleServiceAccount struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	PrivateKey  string `json:"private_key"`
+	ClientEmail string `json:"client_email"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// googleTokenSource exchanges signed JWTs of a service account for access
+// tokens, which are cached until shortly before they expire
+type googleTokenSource struct {
+	account googleServiceAccount
+	key     *rsa.PrivateKey
+	client  *apiClient
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// readGoogleServiceAccount reads a service account key file
+func readGoogleServiceAccount(filePath string, client *apiClient) (*googleTokenSource, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account file %s: %w", filePath, err)
+	}
+
+	var account googleServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse service account file %s: %w", filePath, err)
+	}
+	if account.Type != "service_account" {
+		return nil, fmt.Errorf("%s is not a service account key file", filePath)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	key, err := parseRSAPrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %w", filePath, err)
+	}
+
+	return &googleTokenSource{account: account, key: key, client: client}, nil
+}
+
+// parseRSAPrivateKey decodes a PEM encoded PKCS#8 or PKCS#1 RSA private key
+func parseRSAPrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Token returns a valid access token
+func (s *googleTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	body, err := s.client.do(ctx, "Google OAuth", func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	})
+	if err != nil {
+		// Rejected token requests, like invalid_grant, mean the credentials are wrong
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Kind == ErrBadRequest {
+			apiErr.Kind = ErrAuth
+		}
+		return "", fmt.Errorf("failed to request Google access token: %w", err)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse Google token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("Google token response contained no access token")
+	}
+
+	// Renew the token a minute before it expires
+	s.token = result.AccessToken
+	s.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// assertion returns the signed JWT exchanged for an access token
+func (s *googleTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": googleScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign Google token request: %w", err)
+	}
+
+	return strings.Join([]string{unsigned, encoding.EncodeToString(signature)}, "."), nil
+}
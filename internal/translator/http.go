@@ -0,0 +1,203 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout is the time a single request to a provider may take,
+// unless the timeout option of the provider sets another one in seconds
+const DefaultTimeout = 60 * time.Second
+
+// DefaultRetries is the number of times a request failing with a rate limit
+// or a temporary error is sent again, unless the retries option of the
+// provider sets another one. The wait doubles before every retry.
+const DefaultRetries = 5
+
+const (
+	// initialBackoff is the wait before the first retry
+	initialBackoff = time.Second
+	// maxRetryAfter caps the wait a provider can ask for with Retry-After
+	maxRetryAfter = 2 * time.Minute
+)
+
+// apiClient sends the requests of a provider with the timeout and retries of its options
+type apiClient struct {
+	http       *http.Client
+	maxRetries int
+}
+
+// newAPIClient creates the client of a provider from the timeout and
+// retries options, applied to every request
+func newAPIClient(options Options) (*apiClient, error) {
+	seconds, err := options.Number("timeout", DefaultTimeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	if seconds <= 0 {
+		return nil, fmt.Errorf("option timeout must be a positive number of seconds")
+	}
+	retries, err := options.Number("retries", DefaultRetries)
+	if err != nil {
+		return nil, err
+	}
+	if retries < 0 || retries != float64(int(retries)) {
+		return nil, fmt.Errorf("option retries must be a whole number, 0 or more")
+	}
+	return &apiClient{
+		http:       &http.Client{Timeout: time.Duration(seconds * float64(time.Second))},
+		maxRetries: int(retries),
+	}, nil
+}
+
+// postJSON sends body as JSON to url and decodes the JSON response into
+// result. Responses other than 2xx are returned as an *APIError naming the
+// provider, with the response body as the reason.
+func postJSON(ctx context.Context, client *apiClient, provider, url string, header http.Header, body, result interface{}) error {
+	return sendJSON(ctx, client, provider, http.MethodPost, url, header, body, result)
+}
+
+// sendJSON sends a request with body encoded as JSON, unless body is nil,
+// and decodes the JSON response into result, unless result is nil. Failed
+// requests are retried like those of do.
+func sendJSON(ctx context.Context, client *apiClient, provider, method, url string, header http.Header, body, result interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", provider, err)
+		}
+	}
+
+	respBody, err := client.do(ctx, provider, func() (*http.Request, error) {
+		// Every attempt needs a reader of its own
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(data)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, reader)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse %s response JSON: %w", provider, err)
+	}
+	return nil
+}
+
+// do sends the request newRequest creates and returns the body of its 2xx
+// response. Requests failing with a rate limit or a temporary error are sent
+// again after the wait the provider asked for with Retry-After, or else an
+// exponential backoff with jitter. Other failures are returned right away as
+// an *APIError.
+func (c *apiClient) do(ctx context.Context, provider string, newRequest func() (*http.Request, error)) ([]byte, error) {
+	backoff := initialBackoff
+	for attempt := 0; ; attempt++ {
+		body, err := send(ctx, c.http, provider, newRequest)
+		if err == nil || !isRetryable(err) {
+			return body, err
+		}
+		if attempt == c.maxRetries {
+			if c.maxRetries == 0 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("exceeded maximum retries (%d) for %s API: %w", c.maxRetries, provider, err)
+		}
+
+		wait := backoff + time.Duration(rand.Float64()*float64(backoff)*0.3) // 30% jitter
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.hasRetryAfter {
+			wait = min(apiErr.RetryAfter, maxRetryAfter)
+		}
+		log.Printf("Warning: %v, retrying in %.2f seconds (attempt %d/%d)", err, wait.Seconds(), attempt+1, c.maxRetries)
+
+		// Stop waiting as soon as the run is cancelled
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// send sends a single request and returns the body of its 2xx response
+func send(ctx context.Context, client *http.Client, provider string, newRequest func() (*http.Request, error)) ([]byte, error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", provider, err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Network errors, timeouts included, are worth another attempt
+		return nil, &APIError{Provider: provider, Kind: ErrTransient, Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &APIError{Provider: provider, Kind: ErrTransient, Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, statusError(provider, resp, body)
+	}
+	return body, nil
+}
+
+// chunk splits texts into consecutive groups of at most maxItems texts and,
+// unless a single text is longer, maxChars characters
+func chunk(texts []string, maxItems, maxChars int) [][]string {
+	return chunkBySize(texts, maxItems, maxChars, func(text string) int { return len(text) })
+}
+
+// chunkBySize splits texts into consecutive groups of at most maxItems texts
+// whose sizes add up to at most maxSize, unless a single text is larger
+func chunkBySize(texts []string, maxItems, maxSize int, size func(text string) int) [][]string {
+	var chunks [][]string
+	var current []string
+	total := 0
+	for _, text := range texts {
+		textSize := size(text)
+		if len(current) > 0 && (len(current) == maxItems || total+textSize > maxSize) {
+			chunks = append(chunks, current)
+			current, total = nil, 0
+		}
+		current = append(current, text)
+		total += textSize
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
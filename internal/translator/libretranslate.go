@@ -0,0 +1,148 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Limits of a single LibreTranslate request, kept small because servers
+// can be configured with a character limit
+const (
+	libreMaxTexts = 50
+	libreMaxChars = 5000
+)
+
+// LibreTranslator implements the Translator interface using a self-hosted
+// LibreTranslate server, or any server with a compatible API, so strings
+// never leave the network
+type LibreTranslator struct {
+	url    string
+	apiKey string
+	// format is "text", "html", or "" to send texts with tags as html
+	format string
+	client *apiClient
+}
+
+func init() {
+	Register("libretranslate", newLibreProvider)
+}
+
+// NewLibreTranslator creates a LibreTranslate translator from provider options:
+//
+//   - url: address of the server, defaults to LIBRETRANSLATE_URL
+//   - apiKey: API key if the server requires one, defaults to LIBRETRANSLATE_API_KEY
+//   - format: "text" or "html", by default texts with tags are sent as html
+func NewLibreTranslator(options Options) (*LibreTranslator, error) {
+	t := &LibreTranslator{
+		url:    strings.TrimSuffix(options.String("url"), "/"),
+		apiKey: options.String("apiKey"),
+		format: options.String("format"),
+	}
+	client, err := newAPIClient(options)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	if t.url == "" {
+		t.url = strings.TrimSuffix(os.Getenv("LIBRETRANSLATE_URL"), "/")
+	}
+	if t.apiKey == "" {
+		t.apiKey = os.Getenv("LIBRETRANSLATE_API_KEY")
+	}
+	if t.url == "" {
+		return nil, fmt.Errorf("LibreTranslate provider needs a url or the LIBRETRANSLATE_URL environment variable")
+	}
+	if t.format != "" && t.format != "text" && t.format != "html" {
+		return nil, fmt.Errorf("LibreTranslate format must be 'text' or 'html', got '%s'", t.format)
+	}
+	return t, nil
+}
+
+func newLibreProvider(options Options) (Translator, error) {
+	return NewLibreTranslator(options)
+}
+
+// Translate implements the Translator interface for LibreTranslate
+func (t *LibreTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	translations, err := t.TranslateBatch(ctx, []string{text}, from, to)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatch translates texts, returning the translations in the same order
+func (t *LibreTranslator) TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error) {
+	if from == to {
+		return texts, nil // No need to translate if source and target languages are the same
+	}
+
+	// Plain text and markup are sent in separate requests
+	groups := make(map[string][]int)
+	for i, text := range texts {
+		format := t.format
+		if format == "" {
+			format = "text"
+			if tagRegex.MatchString(text) {
+				format = "html"
+			}
+		}
+		groups[format] = append(groups[format], i)
+	}
+
+	translations := make([]string, len(texts))
+	for _, format := range []string{"text", "html"} {
+		indexes := groups[format]
+		groupTexts := make([]string, len(indexes))
+		for i, index := range indexes {
+			groupTexts[i] = texts[index]
+		}
+
+		offset := 0
+		for _, batch := range chunk(groupTexts, libreMaxTexts, libreMaxChars) {
+			translated, err := t.translate(ctx, batch, from, to, format)
+			if err != nil {
+				return nil, err
+			}
+			for i, text := range translated {
+				translations[indexes[offset+i]] = text
+			}
+			offset += len(batch)
+		}
+	}
+
+	return translations, nil
+}
+
+// translate sends a single request
+func (t *LibreTranslator) translate(ctx context.Context, texts []string, from, to, format string) ([]string, error) {
+	if from == "" {
+		from = "auto"
+	}
+	request := map[string]interface{}{
+		"q":      texts,
+		"source": from,
+		"target": to,
+		"format": format,
+	}
+	if t.apiKey != "" {
+		request["api_key"] = t.apiKey
+	}
+
+	var response struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := postJSON(ctx, t.client, "LibreTranslate", t.url+"/translate", nil, request, &response); err != nil {
+		return nil, err
+	}
+	if len(response.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("LibreTranslate response contained %d translations for %d texts", len(response.TranslatedText), len(texts))
+	}
+	return response.TranslatedText, nil
+}
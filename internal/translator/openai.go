@@ -0,0 +1,278 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bernardoforcillo/globify/internal/icu"
+	"github.com/bernardoforcillo/globify/internal/printf"
+)
+
+// defaultSystemPrompt instructs the model to answer with the translation only
+const defaultSystemPrompt = `You are a professional software localizer translating user interface strings from {from} to {to}.
+Keep ICU message syntax, placeholders like {name}, printf placeholders like %s, tags, line breaks and surrounding whitespace exactly as they are, and only translate the text around them.
+Use the key and description of the string to understand where it is shown.
+Always use the translations of the glossary terms.
+Reply with the translation only, without quotes, comments or explanations.`
+
+// OpenAITranslator implements the Translator interface with a large
+// language model behind an OpenAI compatible chat completions API, like
+// OpenAI itself or local servers such as Ollama and llama.cpp
+type OpenAITranslator struct {
+	baseURL      string
+	apiKey       string
+	model        string
+	temperature  float64
+	systemPrompt string
+	// glossary holds the terms of every target language, "*" for all languages
+	glossary map[string]map[string]string
+	client   *apiClient
+}
+
+func init() {
+	Register("openai", newOpenAIProvider)
+}
+
+// NewOpenAITranslator creates an OpenAI compatible translator from provider options:
+//
+//   - baseURL: defaults to OPENAI_BASE_URL or https://api.openai.com/v1
+//   - apiKey: defaults to OPENAI_API_KEY, local servers usually need none
+//   - model: defaults to OPENAI_MODEL or gpt-4o-mini
+//   - temperature: defaults to 0
+//   - systemPrompt: replaces the default instructions, {from} and {to} are
+//     replaced with the language codes
+//   - glossary: terms and their translation per target language, like
+//     {"fr": {"sign in": "se connecter"}}, "*" holds terms of every language
+func NewOpenAITranslator(options Options) (*OpenAITranslator, error) {
+	t := &OpenAITranslator{
+		baseURL:      strings.TrimSuffix(options.String("baseURL"), "/"),
+		apiKey:       options.String("apiKey"),
+		model:        options.String("model"),
+		systemPrompt: options.String("systemPrompt"),
+		glossary:     make(map[string]map[string]string),
+	}
+	if t.baseURL == "" {
+		t.baseURL = strings.TrimSuffix(os.Getenv("OPENAI_BASE_URL"), "/")
+	}
+	if t.baseURL == "" {
+		t.baseURL = "https://api.openai.com/v1"
+	}
+	if t.apiKey == "" {
+		t.apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	if t.model == "" {
+		t.model = os.Getenv("OPENAI_MODEL")
+	}
+	if t.model == "" {
+		t.model = "gpt-4o-mini"
+	}
+	if t.systemPrompt == "" {
+		t.systemPrompt = defaultSystemPrompt
+	}
+
+	client, err := newAPIClient(options)
+	if err != nil {
+		return nil, err
+	}
+	t.client = client
+
+	temperature, err := options.Number("temperature", 0)
+	if err != nil {
+		return nil, err
+	}
+	t.temperature = temperature
+
+	if glossary, ok := options["glossary"]; ok {
+		languages, ok := glossary.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("option glossary must be an object of terms per language")
+		}
+		for lang, terms := range languages {
+			terms, ok := terms.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("glossary of %s must be an object of terms", lang)
+			}
+			t.glossary[lang] = make(map[string]string, len(terms))
+			for term, translation := range terms {
+				text, ok := translation.(string)
+				if !ok {
+					return nil, fmt.Errorf("glossary translation of '%s' for %s must be a string", term, lang)
+				}
+				t.glossary[lang][term] = text
+			}
+		}
+	}
+
+	return t, nil
+}
+
+func newOpenAIProvider(options Options) (Translator, error) {
+	return NewOpenAITranslator(options)
+}
+
+// Translate implements the Translator interface for chat completion APIs
+func (t *OpenAITranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return t.TranslateMessage(ctx, Message{Text: text}, from, to)
+}
+
+// TranslateWithDescription implements the DescriptionTranslator interface
+func (t *OpenAITranslator) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
+	return t.TranslateMessage(ctx, Message{Text: text, Description: description}, from, to)
+}
+
+// TranslateMessage implements the MessageTranslator interface. Replies
+// that do not keep the ICU placeholders of the message are sent back to the
+// model once with the problem, then rejected.
+func (t *OpenAITranslator) TranslateMessage(ctx context.Context, message Message, from, to string) (string, error) {
+	if message.Text == "" {
+		return "", nil
+	}
+	if from == to {
+		return message.Text, nil // No need to translate if source and target languages are the same
+	}
+
+	replacer := strings.NewReplacer("{from}", from, "{to}", to)
+	messages := []chatMessage{
+		{Role: "system", Content: replacer.Replace(t.systemPrompt)},
+		{Role: "user", Content: t.prompt(message, from, to)},
+	}
+
+	for attempt := 0; ; attempt++ {
+		reply, err := t.complete(ctx, messages)
+		if err != nil {
+			return "", err
+		}
+		translation := cleanReply(reply, message.Text)
+
+		problem := placeholderProblem(message.Text, translation)
+		if problem == "" {
+			return translation, nil
+		}
+		if attempt > 0 {
+			return "", fmt.Errorf("model reply for '%s' is not a valid translation: %s", message.Text, problem)
+		}
+		messages = append(messages,
+			chatMessage{Role: "assistant", Content: reply},
+			chatMessage{Role: "user", Content: "This translation is wrong: " + problem + ". Reply with the corrected translation only."},
+		)
+	}
+}
+
+// prompt builds the request for a single message
+func (t *OpenAITranslator) prompt(message Message, from, to string) string {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Translate this string from %s to %s.\n", from, to)
+	if message.Key != "" {
+		fmt.Fprintf(&prompt, "Key: %s\n", message.Key)
+	}
+	if message.Description != "" {
+		fmt.Fprintf(&prompt, "Description: %s\n", message.Description)
+	}
+	if terms := t.glossaryTerms(message.Text, to); len(terms) > 0 {
+		prompt.WriteString("Glossary:\n")
+		for _, term := range terms {
+			prompt.WriteString("- " + term + "\n")
+		}
+	}
+	prompt.WriteString("String:\n" + message.Text)
+	return prompt.String()
+}
+
+// glossaryTerms returns the glossary entries of the target language whose
+// term appears in text, as "term => translation"
+func (t *OpenAITranslator) glossaryTerms(text, to string) []string {
+	lowerText := strings.ToLower(text)
+	var terms []string
+	for _, lang := range []string{"*", to} {
+		for term, translation := range t.glossary[lang] {
+			if strings.Contains(lowerText, strings.ToLower(term)) {
+				terms = append(terms, term+" => "+translation)
+			}
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// complete sends a chat completion request and returns the reply
+func (t *OpenAITranslator) complete(ctx context.Context, messages []chatMessage) (string, error) {
+	header := http.Header{}
+	if t.apiKey != "" {
+		header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	request := map[string]interface{}{
+		"model":       t.model,
+		"temperature": t.temperature,
+		"messages":    messages,
+	}
+
+	var response struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, t.client, "OpenAI", t.baseURL+"/chat/completions", header, request, &response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("OpenAI response contained no choices")
+	}
+	return response.Choices[0].Message.Content, nil
+}
+
+// cleanReply removes what models tend to add around a translation, like
+// code fences, quotes or whitespace the source does not have
+func cleanReply(reply, source string) string {
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "```") && strings.HasSuffix(reply, "```") {
+		reply = strings.TrimSuffix(reply, "```")
+		// Drop the opening fence along with its language tag
+		if newline := strings.Index(reply, "\n"); newline >= 0 {
+			reply = reply[newline+1:]
+		} else {
+			reply = strings.TrimPrefix(reply, "```")
+		}
+		reply = strings.TrimSpace(reply)
+	}
+	for _, quote := range []string{`"`, "'", "“"} {
+		closing := quote
+		if quote == "“" {
+			closing = "”"
+		}
+		if len(reply) >= 2 && strings.HasPrefix(reply, quote) && strings.HasSuffix(reply, closing) &&
+			!strings.HasPrefix(source, quote) {
+			reply = strings.TrimSuffix(strings.TrimPrefix(reply, quote), closing)
+		}
+	}
+
+	// Keep the surrounding whitespace of the source
+	trimmed := strings.TrimSpace(source)
+	if trimmed == "" {
+		return reply
+	}
+	start := strings.Index(source, trimmed)
+	return source[:start] + reply + source[start+len(trimmed):]
+}
+
+// placeholderProblem describes how translation breaks the ICU or printf
+// placeholders of source, or returns "" when it keeps them. The ICU
+// placeholders of sources that are not valid ICU messages are not checked.
+func placeholderProblem(source, translation string) string {
+	if err := icu.ComparePlaceholders(source, translation); err != nil {
+		return err.Error()
+	}
+	if err := printf.ComparePlaceholders(source, translation); err != nil {
+		return err.Error()
+	}
+	return ""
+}
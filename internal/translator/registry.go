@@ -0,0 +1,266 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bernardoforcillo/globify/internal/config"
+)
+
+// DefaultProvider is the provider used when the configuration names none
+const DefaultProvider = "deepl"
+
+// Options are the settings of a provider block of the configuration
+type Options map[string]interface{}
+
+// String returns the string option name, or "" when it is not set.
+// Environment variables like ${DEEPL_API_KEY} are expanded so secrets can
+// stay out of the configuration file.
+func (o Options) String(name string) string {
+	value, ok := o[name].(string)
+	if !ok {
+		return ""
+	}
+	return os.ExpandEnv(value)
+}
+
+// Number returns the number option name, or fallback when it is not set.
+// Numbers may also be given as strings, like "${TEMPERATURE}".
+func (o Options) Number(name string, fallback float64) (float64, error) {
+	switch value := o[name].(type) {
+	case nil:
+		return fallback, nil
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case string:
+		number, err := strconv.ParseFloat(os.ExpandEnv(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("option %s must be a number: %w", name, err)
+		}
+		return number, nil
+	default:
+		return 0, fmt.Errorf("option %s must be a number", name)
+	}
+}
+
+// Bool returns the boolean option name, or fallback when it is not set.
+// Booleans may also be given as strings, like "${PRESERVE_FORMATTING}".
+func (o Options) Bool(name string, fallback bool) (bool, error) {
+	switch value := o[name].(type) {
+	case nil:
+		return fallback, nil
+	case bool:
+		return value, nil
+	case string:
+		b, err := strconv.ParseBool(os.ExpandEnv(value))
+		if err != nil {
+			return false, fmt.Errorf("option %s must be a boolean: %w", name, err)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("option %s must be a boolean", name)
+	}
+}
+
+// Strings returns the list option name, given either as an array of
+// strings or as a comma separated string, or nil when it is not set
+func (o Options) Strings(name string) ([]string, error) {
+	var items []string
+	switch value := o[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		items = strings.Split(os.ExpandEnv(value), ",")
+	case []string:
+		items = value
+	case []interface{}:
+		for _, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("option %s must be a list of strings", name)
+			}
+			items = append(items, text)
+		}
+	default:
+		return nil, fmt.Errorf("option %s must be a list of strings", name)
+	}
+
+	var list []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// Factory creates a translator from the options of its provider block
+type Factory func(options Options) (Translator, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]Factory)
+)
+
+// Register makes a provider available under name. It panics when name is
+// already registered, like database/sql drivers do.
+func Register(name string, factory Factory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if _, exists := providers[name]; exists {
+		panic(fmt.Sprintf("translator: provider %q registered twice", name))
+	}
+	providers[name] = factory
+}
+
+// Providers returns the sorted names of the registered providers
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates a translator of the provider registered under name
+func New(name string, options Options) (Translator, error) {
+	providersMu.RLock()
+	factory, ok := providers[name]
+	providersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown translation provider '%s', available providers are '%s'", name, strings.Join(Providers(), "', '"))
+	}
+	return factory(options)
+}
+
+// CreateTranslator builds the translator of a provider block of the
+// configuration for the target languages. A nil block uses DeepL with the
+// DEEPL_API_KEY environment variable. Languages with a provider of their
+// own are routed to it, and the provider of the block is only created when
+// a language has none of its own, or when languages is empty.
+func CreateTranslator(provider *config.Provider, languages []string) (Translator, error) {
+	if provider == nil {
+		provider = &config.Provider{}
+	}
+
+	overrides := make(map[string]Translator, len(provider.Languages))
+	for lang, override := range provider.Languages {
+		name, options := override.Name, Options(override.Options)
+		// Overrides without a provider change the options of the block's provider
+		if name == "" || name == provider.Name {
+			name = provider.Name
+			options = mergeOptions(provider.Options, override.Options)
+		}
+		var err error
+		overrides[lang], err = newProvider(name, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create translator for %s: %w", lang, err)
+		}
+	}
+
+	// A block whose languages all have a provider of their own may lack the
+	// settings of its provider, like a DeepL key
+	var translator Translator = unconfiguredTranslator{}
+	if usesBlockProvider(languages, overrides) {
+		var err error
+		translator, err = newProvider(provider.Name, Options(provider.Options))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(overrides) == 0 {
+		return translator, nil
+	}
+	return NewLanguageRouter(translator, overrides), nil
+}
+
+// usesBlockProvider reports whether one of languages has no override, or
+// languages is empty
+func usesBlockProvider(languages []string, overrides map[string]Translator) bool {
+	if len(languages) == 0 {
+		return true
+	}
+	for _, lang := range languages {
+		if _, ok := overrides[lang]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// unconfiguredTranslator stands in for the provider of a block that no
+// target language uses
+type unconfiguredTranslator struct{}
+
+// Translate implements the Translator interface
+func (unconfiguredTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return "", fmt.Errorf("no translation provider is configured for %s", to)
+}
+
+// newProvider creates a translator of the named provider, DeepL when name is empty
+func newProvider(name string, options Options) (Translator, error) {
+	if name == "" {
+		name = DefaultProvider
+	}
+	return New(name, options)
+}
+
+// mergeOptions returns the options of base overridden by those of override
+func mergeOptions(base, override map[string]interface{}) Options {
+	merged := make(Options, len(base)+len(override))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range override {
+		merged[name] = value
+	}
+	return merged
+}
+
+// LanguageRouter sends every translation to the translator of its target
+// language, or to a fallback translator for the other languages
+type LanguageRouter struct {
+	fallback  Translator
+	languages map[string]Translator
+}
+
+// NewLanguageRouter creates a LanguageRouter
+func NewLanguageRouter(fallback Translator, languages map[string]Translator) *LanguageRouter {
+	return &LanguageRouter{fallback: fallback, languages: languages}
+}
+
+// For returns the translator of a target language
+func (r *LanguageRouter) For(to string) Translator {
+	if translator, ok := r.languages[to]; ok {
+		return translator
+	}
+	return r.fallback
+}
+
+// Translate translates text with the translator of the target language
+func (r *LanguageRouter) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return r.For(to).Translate(ctx, text, from, to)
+}
+
+// TranslateWithDescription translates text with the translator of the
+// target language, sending the description along when it supports it
+func (r *LanguageRouter) TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error) {
+	translator := r.For(to)
+	if describer, ok := translator.(DescriptionTranslator); ok {
+		return describer.TranslateWithDescription(ctx, text, description, from, to)
+	}
+	return translator.Translate(ctx, text, from, to)
+}
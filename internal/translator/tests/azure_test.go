@@ -0,0 +1,118 @@
+package translator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+func TestAzureTranslator(t *testing.T) {
+	var textTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/translate" || query.Get("api-version") != "3.0" || query.Get("category") != "my-category" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" || r.Header.Get("Ocp-Apim-Subscription-Region") != "westeurope" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		textTypes = append(textTypes, query.Get("textType"))
+
+		var request []struct {
+			Text string `json:"Text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var response []map[string]interface{}
+		for _, item := range request {
+			var translations []map[string]string
+			for _, to := range query["to"] {
+				translations = append(translations, map[string]string{"text": "[" + to + "] " + item.Text, "to": to})
+			}
+			response = append(response, map[string]interface{}{"translations": translations})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("azure", translator.Options{
+		"apiKey":   "test-key",
+		"region":   "westeurope",
+		"category": "my-category",
+		"endpoint": server.URL,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	azure := trans.(*translator.AzureTranslator)
+
+	// Texts with tags are sent as html, the others as plain text
+	got, err := azure.TranslateBatch(context.Background(), []string{"Hello", "Read the <b>terms</b>", "Bye"}, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	want := []string{"[fr] Hello", "[fr] Read the <b>terms</b>", "[fr] Bye"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(textTypes, []string{"plain", "html"}) {
+		t.Errorf("Requests used text types %v, want [plain html]", textTypes)
+	}
+
+	text, err := trans.Translate(context.Background(), "Hello", "en", "ja")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if text != "[ja] Hello" {
+		t.Errorf("Translate() = %q, want %q", text, "[ja] Hello")
+	}
+}
+
+func TestAzureTranslatorErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"invalid key", `{"error":{"code":401000,"message":"The request is not authorized"}}`, translator.ErrAuth},
+		{"operation not allowed", `{"error":{"code":403000,"message":"The operation is not allowed"}}`, translator.ErrAuth},
+		{"free quota exceeded", `{"error":{"code":403001,"message":"The subscription has exceeded its free quota"}}`, translator.ErrQuota},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var code struct {
+				Error struct {
+					Code int `json:"code"`
+				} `json:"error"`
+			}
+			json.Unmarshal([]byte(tt.body), &code)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(code.Error.Code / 1000)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			trans, err := translator.New("azure", translator.Options{"apiKey": "test-key", "endpoint": server.URL})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			_, err = trans.Translate(context.Background(), "Hello", "en", "fr")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Translate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
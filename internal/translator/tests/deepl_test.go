@@ -0,0 +1,540 @@
+package translator_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+func TestDeeplBaseURL(t *testing.T) {
+	tests := []struct {
+		apiKey string
+		want   string
+	}{
+		{"0a1b2c3d-0000-1111-2222-333344445555:fx", translator.DeeplFreeURL},
+		{"0a1b2c3d-0000-1111-2222-333344445555", translator.DeeplProURL},
+	}
+	for _, tt := range tests {
+		if got := translator.DeeplBaseURL(tt.apiKey); got != tt.want {
+			t.Errorf("DeeplBaseURL(%q) = %q, want %q", tt.apiKey, got, tt.want)
+		}
+
+		trans, err := translator.New("deepl", translator.Options{"apiKey": tt.apiKey})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if got := trans.(*translator.DeeplTranslator).BaseURL(); got != tt.want {
+			t.Errorf("BaseURL() = %q, want %q", got, tt.want)
+		}
+	}
+
+	t.Setenv("DEEPL_BASE_URL", "http://localhost:3000")
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "key:fx"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := trans.(*translator.DeeplTranslator).BaseURL(); got != "http://localhost:3000" {
+		t.Errorf("BaseURL() = %q, want the DEEPL_BASE_URL environment variable", got)
+	}
+}
+
+func TestDeeplTranslatorBatch(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/glossaries" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": []interface{}{}})
+			return
+		}
+		if r.URL.Path != "/v2/translate" || r.Header.Get("Authorization") != "DeepL-Auth-Key secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests++
+
+		var response struct {
+			Translations []map[string]string `json:"translations"`
+		}
+		for _, text := range r.PostForm["text"] {
+			response.Translations = append(response.Translations, map[string]string{"text": "[" + r.PostForm.Get("target_lang") + "] " + text})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL + "/"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	texts := make([]string, 60)
+	want := make([]string, 60)
+	for i := range texts {
+		texts[i] = string(rune('a' + i%26))
+		want[i] = "[fr] " + texts[i]
+	}
+	got, err := trans.(translator.BatchTranslator).TranslateBatch(context.Background(), texts, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %v, want %v", got, want)
+	}
+	if requests != 2 {
+		t.Errorf("TranslateBatch() sent %d requests, want 2", requests)
+	}
+
+	single, err := trans.Translate(context.Background(), "Hello", "en", "de")
+	if err != nil || single != "[de] Hello" {
+		t.Errorf("Translate() = %q, %v", single, err)
+	}
+}
+
+// fakeDeeplGlossaries serves the glossary endpoints of the DeepL API and
+// records the glossary of every translation
+type fakeDeeplGlossaries struct {
+	glossaries []translator.Glossary
+	entries    map[string]string
+	used       []string
+}
+
+func (f *fakeDeeplGlossaries) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/v2/glossaries" && r.Method == http.MethodGet:
+		json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": f.glossaries})
+	case r.URL.Path == "/v2/glossaries" && r.Method == http.MethodPost:
+		var request map[string]string
+		json.NewDecoder(r.Body).Decode(&request)
+		if request["entries_format"] != "tsv" {
+			http.Error(w, "unsupported format", http.StatusBadRequest)
+			return
+		}
+		glossary := translator.Glossary{
+			ID:         fmt.Sprintf("g%d", len(f.glossaries)+1),
+			Name:       request["name"],
+			Ready:      true,
+			SourceLang: request["source_lang"],
+			TargetLang: request["target_lang"],
+			EntryCount: strings.Count(request["entries"], "\n"),
+		}
+		f.glossaries = append(f.glossaries, glossary)
+		f.entries[glossary.ID] = request["entries"]
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(glossary)
+	case strings.HasPrefix(r.URL.Path, "/v2/glossaries/") && r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, "/v2/glossaries/")
+		for i, glossary := range f.glossaries {
+			if glossary.ID == id {
+				f.glossaries = append(f.glossaries[:i], f.glossaries[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	case r.URL.Path == "/v2/translate":
+		r.ParseForm()
+		f.used = append(f.used, r.PostForm.Get("glossary_id"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translations": []map[string]string{{"text": r.PostForm.Get("text")}},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestDeeplGlossaries(t *testing.T) {
+	fake := &fakeDeeplGlossaries{entries: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL, "glossary": "brand"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	deepl := trans.(*translator.DeeplTranslator)
+
+	// Without a glossary translations are sent without glossary_id
+	if _, err := deepl.Translate(context.Background(), "Sign in", "en", "DE"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	glossary, err := deepl.CreateGlossary(context.Background(), deepl.GlossaryName(), "en", "de-CH", []translator.GlossaryEntry{
+		{Source: "Globify", Target: "Globify"},
+		{Source: "sign in", Target: "anmelden"},
+	})
+	if err != nil {
+		t.Fatalf("CreateGlossary() error = %v", err)
+	}
+	if glossary.SourceLang != "en" || glossary.TargetLang != "de" || glossary.EntryCount != 2 {
+		t.Errorf("CreateGlossary() = %+v", glossary)
+	}
+	if want := "Globify\tGlobify\nsign in\tanmelden\n"; fake.entries[glossary.ID] != want {
+		t.Errorf("CreateGlossary() sent entries %q, want %q", fake.entries[glossary.ID], want)
+	}
+
+	// Glossaries of the language pair are used for every variant of the target
+	if _, err := deepl.Translate(context.Background(), "Sign in", "en", "DE"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if _, err := deepl.Translate(context.Background(), "Sign in", "en", "fr"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if want := []string{"", glossary.ID, ""}; !reflect.DeepEqual(fake.used, want) {
+		t.Errorf("Translations used glossaries %q, want %q", fake.used, want)
+	}
+
+	glossaries, err := deepl.ListGlossaries(context.Background())
+	if err != nil {
+		t.Fatalf("ListGlossaries() error = %v", err)
+	}
+	if found := translator.FindGlossaries(glossaries, "brand", "EN", "de"); len(found) != 1 || found[0].ID != glossary.ID {
+		t.Errorf("FindGlossaries() = %v", found)
+	}
+
+	if err := deepl.DeleteGlossary(context.Background(), glossary.ID); err != nil {
+		t.Fatalf("DeleteGlossary() error = %v", err)
+	}
+	if _, err := deepl.Translate(context.Background(), "Sign in", "en", "de"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if used := fake.used[len(fake.used)-1]; used != "" {
+		t.Errorf("Translation used deleted glossary %q", used)
+	}
+	if err := deepl.DeleteGlossary(context.Background(), glossary.ID); err == nil {
+		t.Errorf("DeleteGlossary() of a deleted glossary should fail")
+	}
+}
+
+func TestDeeplGlossaryListingFailure(t *testing.T) {
+	var listings int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/glossaries" {
+			listings++
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translations": []map[string]string{{"text": "Anmelden"}},
+		})
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	// Without a glossary option a failed listing only leaves glossaries out, once
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if got, err := trans.Translate(context.Background(), "Sign in", "en", "de"); err != nil || got != "Anmelden" {
+			t.Fatalf("Translate() = %q, %v", got, err)
+		}
+	}
+	if listings != 1 {
+		t.Errorf("Glossaries were listed %d times, want 1", listings)
+	}
+	if !strings.Contains(logs.String(), "failed to list DeepL glossaries") {
+		t.Errorf("Logs %q do not warn about the glossaries", logs.String())
+	}
+
+	// A glossary named in the options must be found
+	trans, err = translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL, "glossary": "brand"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := trans.Translate(context.Background(), "Sign in", "en", "de"); err == nil {
+		t.Errorf("Translate() with an unlisted glossary should return an error")
+	}
+}
+
+func TestReadGlossaryFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []translator.GlossaryEntry
+		wantErr bool
+	}{
+		{
+			name:    "CSV with comments and quotes",
+			file:    "de.csv",
+			content: "# Brand terms\nGlobify,Globify\n\"sign in, now\", \"jetzt anmelden\"\n",
+			want:    []translator.GlossaryEntry{{Source: "Globify", Target: "Globify"}, {Source: "sign in, now", Target: "jetzt anmelden"}},
+		},
+		{
+			name:    "TSV",
+			file:    "fr.tsv",
+			content: "sign in\tse connecter\n\"quoted\"\t\"cité\"\n",
+			want:    []translator.GlossaryEntry{{Source: "sign in", Target: "se connecter"}, {Source: "\"quoted\"", Target: "\"cité\""}},
+		},
+		{name: "Missing translation", file: "es.csv", content: "Globify\n", wantErr: true},
+		{name: "Duplicate term", file: "it.csv", content: "a,b\na,c\n", wantErr: true},
+		{name: "Empty file", file: "pt.csv", content: "# Nothing yet\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write glossary file: %v", err)
+			}
+			got, err := translator.ReadGlossaryFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadGlossaryFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadGlossaryFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeeplTranslatorOptions(t *testing.T) {
+	var forms []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/glossaries" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": []interface{}{}})
+			return
+		}
+		r.ParseForm()
+		form := make(map[string]string)
+		for name := range r.PostForm {
+			form[name] = r.PostForm.Get(name)
+		}
+		forms = append(forms, form)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translations": []map[string]string{{"text": r.PostForm.Get("text")}},
+		})
+	}))
+	defer server.Close()
+
+	trans, err := translator.CreateTranslator(&config.Provider{
+		Name: "deepl",
+		Options: map[string]interface{}{
+			"apiKey":             "secret",
+			"baseURL":            server.URL,
+			"formality":          "prefer_more",
+			"context":            "A photo sharing app",
+			"preserveFormatting": true,
+			"splitSentences":     "nonewlines",
+			"tagHandling":        "xml",
+			"ignoreTags":         []interface{}{"code", "brand"},
+		},
+		Languages: map[string]*config.Provider{
+			"de": {Options: map[string]interface{}{"formality": "less", "preserveFormatting": "false"}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateTranslator() error = %v", err)
+	}
+
+	if _, err := trans.(translator.DescriptionTranslator).TranslateWithDescription(context.Background(), "Share", "Button label", "en", "fr"); err != nil {
+		t.Fatalf("TranslateWithDescription() error = %v", err)
+	}
+	if _, err := trans.Translate(context.Background(), "Share", "en", "de"); err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+
+	want := []map[string]string{
+		{
+			"text":                "Share",
+			"source_lang":         "en",
+			"target_lang":         "fr",
+			"context":             "A photo sharing app\nButton label",
+			"formality":           "prefer_more",
+			"preserve_formatting": "1",
+			"split_sentences":     "nonewlines",
+			"tag_handling":        "xml",
+			"ignore_tags":         "code,brand",
+		},
+		{
+			"text":                "Share",
+			"source_lang":         "en",
+			"target_lang":         "de",
+			"context":             "A photo sharing app",
+			"formality":           "less",
+			"preserve_formatting": "0",
+			"split_sentences":     "nonewlines",
+			"tag_handling":        "xml",
+			"ignore_tags":         "code,brand",
+		},
+	}
+	if !reflect.DeepEqual(forms, want) {
+		t.Errorf("Requests = %v, want %v", forms, want)
+	}
+
+	invalid := []translator.Options{
+		{"formality": "casual"},
+		{"splitSentences": "always"},
+		{"tagHandling": "markdown"},
+		{"ignoreTags": "code"},
+		{"preserveFormatting": "sometimes"},
+	}
+	for _, options := range invalid {
+		options["apiKey"] = "secret"
+		if _, err := translator.New("deepl", options); err == nil {
+			t.Errorf("New(%v) should fail", options)
+		}
+	}
+}
+
+func TestDeeplUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v2/usage" || r.Header.Get("Authorization") != "DeepL-Auth-Key secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]int64{"character_count": 180118, "character_limit": 500000})
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	usage, err := trans.(translator.UsageReporter).Usage(context.Background())
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	want := &translator.Usage{Provider: "DeepL", Characters: 180118, Limit: 500000}
+	if !reflect.DeepEqual(usage, want) || usage.Remaining() != 319882 {
+		t.Errorf("Usage() = %+v, want %+v", usage, want)
+	}
+}
+
+func TestDeeplErrors(t *testing.T) {
+	type reply struct {
+		status     int
+		retryAfter string
+	}
+	tests := []struct {
+		name      string
+		replies   []reply
+		wantKind  error
+		wantFatal bool
+		// wantRequests is the number of requests sent, with one retry allowed
+		wantRequests int
+	}{
+		{name: "Success after rate limit", replies: []reply{{http.StatusTooManyRequests, "0"}, {http.StatusOK, ""}}, wantRequests: 2},
+		{name: "Success after server error", replies: []reply{{http.StatusServiceUnavailable, "0"}, {http.StatusOK, ""}}, wantRequests: 2},
+		{name: "Rate limit", replies: []reply{{http.StatusTooManyRequests, "0"}, {http.StatusTooManyRequests, "0"}}, wantKind: translator.ErrRateLimit, wantRequests: 2},
+		{name: "Server error", replies: []reply{{http.StatusInternalServerError, "0"}, {http.StatusBadGateway, "0"}}, wantKind: translator.ErrTransient, wantRequests: 2},
+		{name: "Invalid key", replies: []reply{{http.StatusForbidden, ""}}, wantKind: translator.ErrAuth, wantFatal: true, wantRequests: 1},
+		{name: "Quota exceeded", replies: []reply{{456, ""}}, wantKind: translator.ErrQuota, wantFatal: true, wantRequests: 1},
+		{name: "Bad request", replies: []reply{{http.StatusBadRequest, ""}}, wantKind: translator.ErrBadRequest, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reply := tt.replies[min(requests, len(tt.replies)-1)]
+				requests++
+				if reply.retryAfter != "" {
+					w.Header().Set("Retry-After", reply.retryAfter)
+				}
+				if reply.status != http.StatusOK {
+					http.Error(w, http.StatusText(reply.status), reply.status)
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"translations": []map[string]string{{"text": "Hallo"}}})
+			}))
+			defer server.Close()
+
+			trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL, "retries": 1})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := trans.Translate(context.Background(), "Hello", "", "DE")
+			if tt.wantKind == nil {
+				if err != nil || got != "Hallo" {
+					t.Errorf("Translate() = %q, %v, want Hallo", got, err)
+				}
+			} else {
+				var apiErr *translator.APIError
+				if !errors.Is(err, tt.wantKind) || !errors.As(err, &apiErr) {
+					t.Fatalf("Translate() error = %v, want an APIError of kind %v", err, tt.wantKind)
+				}
+				if apiErr.Provider != "DeepL" || apiErr.StatusCode != tt.replies[len(tt.replies)-1].status {
+					t.Errorf("Translate() error = %+v", apiErr)
+				}
+				if translator.IsFatal(err) != tt.wantFatal {
+					t.Errorf("IsFatal(%v) = %t, want %t", err, !tt.wantFatal, tt.wantFatal)
+				}
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("Translate() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDeeplTranslatorBatchSize(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/glossaries" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"glossaries": []interface{}{}})
+			return
+		}
+		if r.ContentLength > 128*1024 {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests++
+
+		var response struct {
+			Translations []map[string]string `json:"translations"`
+		}
+		for _, text := range r.PostForm["text"] {
+			response.Translations = append(response.Translations, map[string]string{"text": text})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("deepl", translator.Options{"apiKey": "secret", "baseURL": server.URL, "context": "Labels of a photo app"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// Texts of 3 KB in UTF-8 take 9 KB each once URL encoded in the form body
+	texts := make([]string, 50)
+	for i := range texts {
+		texts[i] = strings.Repeat("写真", 500)
+	}
+	got, err := trans.(translator.BatchTranslator).TranslateBatch(context.Background(), texts, "ja", "zh")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, texts) {
+		t.Errorf("TranslateBatch() returned %d translations, want %d", len(got), len(texts))
+	}
+	if requests < 4 {
+		t.Errorf("TranslateBatch() sent %d requests, want the texts split by their encoded size", requests)
+	}
+}
@@ -0,0 +1,195 @@
+package translator_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+func TestGoogleTranslatorV2(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/language/translate/v2" || r.Header.Get("X-Goog-Api-Key") != "test-key" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+
+		var request struct {
+			Q      []string `json:"q"`
+			Source string   `json:"source"`
+			Target string   `json:"target"`
+			Format string   `json:"format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if request.Source != "en" || request.Format != "html" {
+			http.Error(w, "unexpected source or format", http.StatusBadRequest)
+			return
+		}
+
+		var translations []map[string]string
+		for _, text := range request.Q {
+			translations = append(translations, map[string]string{"translatedText": "[" + request.Target + "] " + text})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"translations": translations}})
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("google", translator.Options{
+		"apiKey":  "test-key",
+		"baseURL": server.URL,
+		"format":  "html",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// More texts than a single v2 request accepts are split into several requests
+	texts := make([]string, 130)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("Text <b>%d</b>", i)
+	}
+	translations, err := trans.(*translator.GoogleTranslator).TranslateBatch(context.Background(), texts, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("TranslateBatch() sent %d requests, want 2", requests)
+	}
+	if len(translations) != len(texts) || translations[129] != "[fr] Text <b>129</b>" {
+		t.Errorf("TranslateBatch() = %v", translations)
+	}
+
+	got, err := trans.Translate(context.Background(), "Hello", "en", "de")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got != "[de] Hello" {
+		t.Errorf("Translate() = %q, want %q", got, "[de] Hello")
+	}
+}
+
+func TestGoogleTranslatorV3ServiceAccount(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	var tokenRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || strings.Count(r.FormValue("assertion"), ".") != 2 {
+			http.Error(w, "invalid token request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "test-token", "expires_in": 3600})
+	})
+	mux.HandleFunc("/v3/projects/my-project/locations/global:translateText", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var request struct {
+			Contents           []string `json:"contents"`
+			TargetLanguageCode string   `json:"targetLanguageCode"`
+			MimeType           string   `json:"mimeType"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.MimeType != "text/plain" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		var translations []map[string]string
+		for _, text := range request.Contents {
+			translations = append(translations, map[string]string{"translatedText": "[" + request.TargetLanguageCode + "] " + text})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"translations": translations})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "my-project",
+		"client_email": "globify@my-project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":    server.URL + "/token",
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal credentials: %v", err)
+	}
+	credentialsPath := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(credentialsPath, credentials, 0600); err != nil {
+		t.Fatalf("Failed to write credentials: %v", err)
+	}
+
+	trans, err := translator.New("google", translator.Options{
+		"credentials": credentialsPath,
+		"baseURL":     server.URL,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, to := range []string{"ja", "ko"} {
+		got, err := trans.Translate(context.Background(), "Hello", "en", to)
+		if err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+		if want := "[" + to + "] Hello"; got != want {
+			t.Errorf("Translate() = %q, want %q", got, want)
+		}
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Requested %d access tokens, want 1", tokenRequests)
+	}
+
+	if _, err := translator.New("google", translator.Options{"apiKey": "key", "version": "v3"}); err == nil {
+		t.Errorf("New() with v3 and an API key should return an error")
+	}
+}
+
+func TestGoogleTranslatorKeyNotLogged(t *testing.T) {
+	// A closed server fails every request with a network error, which is retried
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var logs strings.Builder
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	trans, err := translator.New("google", translator.Options{
+		"apiKey":  "secret-key",
+		"baseURL": server.URL,
+		"retries": 1,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = trans.Translate(context.Background(), "Hello", "en", "fr")
+	if err == nil {
+		t.Fatalf("Translate() should fail when the server is down")
+	}
+	if !strings.Contains(logs.String(), "retrying") {
+		t.Errorf("Translate() should log the retry, got %q", logs.String())
+	}
+	if strings.Contains(logs.String(), "secret-key") || strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("Translate() leaked the API key: log %q, error %v", logs.String(), err)
+	}
+}
@@ -0,0 +1,96 @@
+package translator_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+func TestLibreTranslator(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Q      []string `json:"q"`
+			Source string   `json:"source"`
+			Target string   `json:"target"`
+			Format string   `json:"format"`
+			APIKey string   `json:"api_key"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/translate" || request.APIKey != "secret" || request.Source != "en" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Invalid API key"})
+			return
+		}
+		formats = append(formats, request.Format)
+
+		var translations []string
+		for _, text := range request.Q {
+			translations = append(translations, "["+request.Target+"] "+text)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"translatedText": translations})
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("libretranslate", translator.Options{"url": server.URL + "/", "apiKey": "secret"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := trans.(*translator.LibreTranslator).TranslateBatch(context.Background(), []string{"<b>Bold</b>", "Hello", "World"}, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	want := []string{"[fr] <b>Bold</b>", "[fr] Hello", "[fr] World"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(formats, []string{"text", "html"}) {
+		t.Errorf("Requests used formats %v, want [text html]", formats)
+	}
+
+	wrongKey, err := translator.New("libretranslate", translator.Options{"url": server.URL, "apiKey": "wrong"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := wrongKey.Translate(context.Background(), "Hello", "en", "fr"); err == nil {
+		t.Errorf("Translate() with a wrong API key should return an error")
+	}
+}
+
+func TestLibreTranslatorTimeout(t *testing.T) {
+	// The server answers only once the test ends, long after the client gave up
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	trans, err := translator.New("libretranslate", translator.Options{"url": server.URL, "timeout": 0.05, "retries": 0})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := trans.Translate(context.Background(), "Hello", "en", "fr"); err == nil {
+		t.Errorf("Translate() should time out")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := trans.Translate(ctx, "Hello", "en", "fr"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Translate() with a cancelled context error = %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := translator.New("libretranslate", translator.Options{"url": server.URL, "timeout": 0}); err == nil {
+		t.Errorf("New() with a zero timeout should return an error")
+	}
+}
@@ -0,0 +1,137 @@
+package translator_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+func TestOpenAITranslator(t *testing.T) {
+	var prompts []string
+	replies := []string{
+		// The first reply loses the placeholder and is sent back to the model
+		"Bienvenue, nom ! Connectez-vous.",
+		"```\n\"Bienvenue, {name} ! Connectez-vous.\"\n```",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var request struct {
+			Model       string  `json:"model"`
+			Temperature float64 `json:"temperature"`
+			Messages    []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "llama3" || request.Temperature != 0.2 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		prompts = append(prompts, request.Messages[len(request.Messages)-1].Content)
+		if request.Messages[0].Content != "Translate en to fr." {
+			http.Error(w, "unexpected system prompt "+request.Messages[0].Content, http.StatusBadRequest)
+			return
+		}
+
+		reply := replies[len(prompts)-1]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("openai", translator.Options{
+		"baseURL":      server.URL + "/v1",
+		"apiKey":       "test-key",
+		"model":        "llama3",
+		"temperature":  0.2,
+		"systemPrompt": "Translate {from} to {to}.",
+		"glossary": map[string]interface{}{
+			"fr": map[string]interface{}{"sign in": "se connecter", "sign out": "se déconnecter"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := trans.(translator.MessageTranslator).TranslateMessage(context.Background(), translator.Message{
+		Key:         "home.welcome",
+		Text:        "Welcome, {name}! Sign in.",
+		Description: "Greeting on the home page",
+	}, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateMessage() error = %v", err)
+	}
+	if want := "Bienvenue, {name} ! Connectez-vous."; got != want {
+		t.Errorf("TranslateMessage() = %q, want %q", got, want)
+	}
+
+	if len(prompts) != 2 {
+		t.Fatalf("Sent %d prompts, want 2", len(prompts))
+	}
+	for _, want := range []string{"Key: home.welcome", "Description: Greeting on the home page", "sign in => se connecter", "Welcome, {name}! Sign in."} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("Prompt %q does not contain %q", prompts[0], want)
+		}
+	}
+	if strings.Contains(prompts[0], "sign out") {
+		t.Errorf("Prompt %q contains a glossary term the text does not use", prompts[0])
+	}
+	if !strings.Contains(prompts[1], "[name]") {
+		t.Errorf("Retry prompt %q does not name the missing placeholder", prompts[1])
+	}
+}
+
+func TestOpenAITranslatorKeepsPrintfPlaceholders(t *testing.T) {
+	var prompts []string
+	replies := []string{
+		// The first reply drops a printf placeholder and is sent back to the model
+		"Fichiers enregistrés dans %s",
+		"%d fichiers enregistrés dans %s",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		prompts = append(prompts, request.Messages[len(request.Messages)-1].Content)
+
+		reply := replies[len(prompts)-1]
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": reply}}},
+		})
+	}))
+	defer server.Close()
+
+	trans, err := translator.New("openai", translator.Options{"baseURL": server.URL + "/v1", "apiKey": "test-key"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := trans.(translator.MessageTranslator).TranslateMessage(context.Background(), translator.Message{
+		Key:  "saved",
+		Text: "Saved %d files in %s",
+	}, "en", "fr")
+	if err != nil {
+		t.Fatalf("TranslateMessage() error = %v", err)
+	}
+	if want := "%d fichiers enregistrés dans %s"; got != want {
+		t.Errorf("TranslateMessage() = %q, want %q", got, want)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "[%d, %s]") {
+		t.Errorf("Sent prompts %q, want a retry naming the printf placeholders", prompts)
+	}
+}
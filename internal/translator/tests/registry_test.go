@@ -0,0 +1,105 @@
+package translator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/config"
+	"github.com/bernardoforcillo/globify/internal/translator"
+)
+
+// prefixTranslator prefixes translations with its name and the options it was created with
+type prefixTranslator struct {
+	prefix string
+}
+
+func (p *prefixTranslator) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return fmt.Sprintf("[%s %s] %s", p.prefix, to, text), nil
+}
+
+func init() {
+	translator.Register("test-prefix", func(options translator.Options) (translator.Translator, error) {
+		prefix := options.String("prefix")
+		if prefix == "" {
+			return nil, fmt.Errorf("prefix option is required")
+		}
+		return &prefixTranslator{prefix: prefix}, nil
+	})
+}
+
+func TestProviders(t *testing.T) {
+	providers := strings.Join(translator.Providers(), ",")
+	for _, name := range []string{"deepl", "test-prefix"} {
+		if !strings.Contains(providers, name) {
+			t.Errorf("Providers() = %s, want it to contain %s", providers, name)
+		}
+	}
+
+	if _, err := translator.New("missing", nil); err == nil || !strings.Contains(err.Error(), "test-prefix") {
+		t.Errorf("New() with an unknown provider error = %v, want the available providers", err)
+	}
+}
+
+func TestCreateTranslatorRoutesLanguages(t *testing.T) {
+	t.Setenv("GLOBIFY_TEST_PREFIX", "env")
+
+	trans, err := translator.CreateTranslator(&config.Provider{
+		Name:    "test-prefix",
+		Options: map[string]interface{}{"prefix": "${GLOBIFY_TEST_PREFIX}"},
+		Languages: map[string]*config.Provider{
+			// Without a name only the options change
+			"ja": {Options: map[string]interface{}{"prefix": "ja-only"}},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateTranslator() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"fr": "[env fr] Hello",
+		"ja": "[ja-only ja] Hello",
+	}
+	for to, want := range tests {
+		got, err := trans.Translate(context.Background(), "Hello", "en", to)
+		if err != nil {
+			t.Fatalf("Translate() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Translate() to %s = %q, want %q", to, got, want)
+		}
+	}
+
+	_, err = translator.CreateTranslator(&config.Provider{
+		Name:      "test-prefix",
+		Options:   map[string]interface{}{"prefix": "p"},
+		Languages: map[string]*config.Provider{"de": {Name: "test-prefix", Options: map[string]interface{}{"prefix": ""}}},
+	}, nil)
+	if err == nil {
+		t.Errorf("CreateTranslator() with an invalid override should return an error")
+	}
+}
+
+func TestCreateTranslatorSkipsUnusedProvider(t *testing.T) {
+	t.Setenv("DEEPL_API_KEY", "")
+
+	// Every target language uses another provider, so DeepL needs no key
+	overrides := map[string]*config.Provider{
+		"fr": {Name: "test-prefix", Options: map[string]interface{}{"prefix": "fr"}},
+		"de": {Name: "test-prefix", Options: map[string]interface{}{"prefix": "de"}},
+	}
+	trans, err := translator.CreateTranslator(&config.Provider{Languages: overrides}, []string{"fr", "de"})
+	if err != nil {
+		t.Fatalf("CreateTranslator() error = %v", err)
+	}
+	got, err := trans.Translate(context.Background(), "Hello", "en", "de")
+	if err != nil || got != "[de de] Hello" {
+		t.Errorf("Translate() = %q, %v, want %q", got, err, "[de de] Hello")
+	}
+
+	// A language without an override still needs the provider of the block
+	if _, err := translator.CreateTranslator(&config.Provider{Languages: overrides}, []string{"fr", "de", "ja"}); err == nil {
+		t.Errorf("CreateTranslator() without a DeepL key should return an error for ja")
+	}
+}
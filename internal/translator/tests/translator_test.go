@@ -1,6 +1,7 @@
 package translator_test
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -15,7 +16,7 @@ func TestCreateTranslator(t *testing.T) {
 	}
 	
 	// Test creating a translator
-	tr, err := translator.CreateTranslator()
+	tr, err := translator.CreateTranslator(nil, nil)
 	if err != nil {
 		t.Errorf("CreateTranslator() error = %v", err)
 	}
@@ -39,7 +40,7 @@ func TestDeeplTranslator(t *testing.T) {
 	}
 	
 	// Test the Translate method
-	result, err := tr.Translate("Hello, world!", "en", "fr")
+	result, err := tr.Translate(context.Background(), "Hello, world!", "en", "fr")
 	if err != nil {
 		t.Errorf("Translate() error = %v", err)
 	}
@@ -1,11 +1,90 @@
 package translator
 
+import "context"
+
 // Translator defines the interface for translation services
 type Translator interface {
-	Translate(text, from, to string) (string, error)
+	Translate(ctx context.Context, text, from, to string) (string, error)
+}
+
+// DescriptionTranslator is implemented by translators that can use the
+// description of a message, like the description of an ARB message, as
+// context to improve its translation
+type DescriptionTranslator interface {
+	Translator
+	TranslateWithDescription(ctx context.Context, text, description, from, to string) (string, error)
+}
+
+// BatchTranslator is implemented by translators that can translate many
+// texts in a single request. Processors send them the strings of a file in
+// batches instead of one request per string.
+type BatchTranslator interface {
+	Translator
+	// TranslateBatch returns the translations of texts in the same order
+	TranslateBatch(ctx context.Context, texts []string, from, to string) ([]string, error)
+}
+
+// DescriptionBatchTranslator is implemented by batch translators that can
+// send a description shared by every text of a batch, like the context of
+// DeepL. Processors batch the strings that have the same description.
+type DescriptionBatchTranslator interface {
+	BatchTranslator
+	// TranslateBatchWithDescription returns the translations of texts, all
+	// described by description, in the same order
+	TranslateBatchWithDescription(ctx context.Context, texts []string, description, from, to string) ([]string, error)
+}
+
+// Usage is the character quota of a translation provider account
+type Usage struct {
+	Provider string
+	// Characters is the number of characters translated in the current billing period
+	Characters int64
+	// Limit is the number of characters the account may translate in the
+	// period, 0 when it has no limit
+	Limit int64
 }
 
-// Factory function to create a translator based on environment variables
-func CreateTranslator() (Translator, error) {
-	return NewDeeplTranslator()
-}
\ No newline at end of file
+// Remaining returns the number of characters left in the period, or -1
+// when the account has no limit
+func (u *Usage) Remaining() int64 {
+	if u.Limit == 0 {
+		return -1
+	}
+	if u.Characters > u.Limit {
+		return 0
+	}
+	return u.Limit - u.Characters
+}
+
+// UsageReporter is implemented by translators that can report the character
+// quota of their account, so runs exceeding it can be stopped before they start
+type UsageReporter interface {
+	Translator
+	Usage(ctx context.Context) (*Usage, error)
+}
+
+// Message is a string to translate along with what is known about it
+type Message struct {
+	// Key is the dotted path of the message in the translation file
+	Key         string
+	Text        string
+	Description string
+}
+
+// MessageTranslator is implemented by translators that translate whole
+// messages, placeholders and ICU syntax included, using their key and
+// description as context. Processors send them complete messages instead of
+// the fragments between placeholders.
+type MessageTranslator interface {
+	Translator
+	TranslateMessage(ctx context.Context, message Message, from, to string) (string, error)
+}
+
+// ForLanguage returns the translator t uses for the target language to,
+// which is t itself unless it routes languages to different translators
+func ForLanguage(t Translator, to string) Translator {
+	if router, ok := t.(*LanguageRouter); ok {
+		return router.For(to)
+	}
+	return t
+}
@@ -0,0 +1,104 @@
+package xliff_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bernardoforcillo/globify/internal/xliff"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	units := []xliff.Unit{
+		{Key: "greeting", Source: "Hello <b>{name}</b>", State: xliff.StateInitial, Notes: []string{"Home screen"}},
+		{Key: "nested.key1", Source: "Value", Target: "Valeur", State: xliff.StateInitial},
+		{Key: "%lld items", Source: "%lld items", Target: "%lld éléments", State: xliff.StateFinal},
+	}
+
+	for _, version := range []string{xliff.Version12, xliff.Version20} {
+		t.Run(version, func(t *testing.T) {
+			doc := &xliff.Document{
+				Version:        version,
+				SourceLanguage: "en",
+				TargetLanguage: "fr",
+				Original:       "fr.json",
+				Units:          units,
+			}
+
+			buffer := &bytes.Buffer{}
+			if err := xliff.Write(buffer, doc); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if !strings.Contains(buffer.String(), `version="`+version+`"`) {
+				t.Errorf("Write() wrote:\n%s", buffer.String())
+			}
+
+			read, err := xliff.Read(buffer)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(read, doc) {
+				t.Errorf("Read() got = %+v, want %+v", read, doc)
+			}
+		})
+	}
+}
+
+func TestReadStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []string
+	}{
+		{
+			name: "XLIFF 1.2",
+			document: `<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file original="fr.json" source-language="en" target-language="fr" datatype="plaintext">
+    <body>
+      <trans-unit id="a"><source>A</source><target state="needs-review-translation">A fr</target></trans-unit>
+      <trans-unit id="b"><source>B</source><target state="signed-off">B fr</target></trans-unit>
+      <trans-unit id="c"><source>C</source><target>C fr</target></trans-unit>
+      <trans-unit id="d"><source>D</source></trans-unit>
+    </body>
+  </file>
+</xliff>`,
+			want: []string{xliff.StateTranslated, xliff.StateReviewed, xliff.StateTranslated, xliff.StateInitial},
+		},
+		{
+			name: "XLIFF 2.0",
+			document: `<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="en" trgLang="fr">
+  <file id="f1">
+    <unit id="a"><segment state="reviewed"><source>A</source><target>A fr</target></segment></unit>
+    <unit id="b">
+      <segment state="final"><source>B. </source><target>B fr. </target></segment>
+      <segment state="initial"><source>Again</source></segment>
+    </unit>
+    <unit id="c"><segment><source>C</source><target>C fr</target></segment></unit>
+  </file>
+</xliff>`,
+			want: []string{xliff.StateReviewed, xliff.StateInitial, xliff.StateTranslated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := xliff.Read(strings.NewReader(tt.document))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+
+			var states []string
+			for _, unit := range doc.Units {
+				states = append(states, unit.State)
+			}
+			if !reflect.DeepEqual(states, tt.want) {
+				t.Errorf("Read() states = %v, want %v", states, tt.want)
+			}
+		})
+	}
+
+	if _, err := xliff.Read(strings.NewReader(`<xliff version="3.0"></xliff>`)); err == nil {
+		t.Errorf("Read() of an unsupported version should return error")
+	}
+}
@@ -0,0 +1,369 @@
+package xliff
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Supported XLIFF versions
+const (
+	Version12 = "1.2"
+	Version20 = "2.0"
+)
+
+// States of a unit, named after the XLIFF 2.0 segment states. XLIFF 1.2
+// states are converted from and to the closest of them.
+const (
+	// StateInitial means the unit still has to be translated
+	StateInitial = "initial"
+	// StateTranslated means the unit has a translation that was not reviewed yet
+	StateTranslated = "translated"
+	// StateReviewed means the translation of the unit was reviewed
+	StateReviewed = "reviewed"
+	// StateFinal means the translation of the unit is finished
+	StateFinal = "final"
+)
+
+// Document is a bilingual XLIFF file for a single target language
+type Document struct {
+	Version        string
+	SourceLanguage string
+	TargetLanguage string
+	// Original names the file the units come from
+	Original string
+	Units    []Unit
+}
+
+// Unit is a single translatable string
+type Unit struct {
+	// Key is the dotted path of the string in the translation file
+	Key    string
+	Source string
+	Target string
+	State  string
+	Notes  []string
+}
+
+// Done reports whether the unit holds a translation that can be imported
+func (u Unit) Done() bool {
+	return u.Target != "" && u.State != StateInitial
+}
+
+// Write encodes doc as XLIFF of its version
+func Write(w io.Writer, doc *Document) error {
+	var root interface{}
+	switch doc.Version {
+	case Version12:
+		root = newDocument12(doc)
+	case Version20:
+		root = newDocument20(doc)
+	default:
+		return fmt.Errorf("unsupported XLIFF version %q", doc.Version)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("failed to encode XLIFF: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Read decodes an XLIFF 1.2 or 2.0 document holding a single file
+func Read(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var probe struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+	}
+	if err := xml.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse XLIFF: %w", err)
+	}
+	if probe.XMLName.Local != "xliff" {
+		return nil, fmt.Errorf("not an XLIFF document, root element is <%s>", probe.XMLName.Local)
+	}
+
+	switch probe.Version {
+	case Version12:
+		var root document12
+		if err := xml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse XLIFF: %w", err)
+		}
+		return root.document()
+	case Version20:
+		var root document20
+		if err := xml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("failed to parse XLIFF: %w", err)
+		}
+		return root.document()
+	default:
+		return nil, fmt.Errorf("unsupported XLIFF version %q", probe.Version)
+	}
+}
+
+// document12 is the XML structure of an XLIFF 1.2 document
+type document12 struct {
+	XMLName xml.Name `xml:"xliff"`
+	Version string   `xml:"version,attr"`
+	Xmlns   string   `xml:"xmlns,attr,omitempty"`
+	Files   []file12 `xml:"file"`
+}
+
+type file12 struct {
+	Original       string   `xml:"original,attr"`
+	SourceLanguage string   `xml:"source-language,attr"`
+	TargetLanguage string   `xml:"target-language,attr,omitempty"`
+	Datatype       string   `xml:"datatype,attr"`
+	Units          []unit12 `xml:"body>trans-unit"`
+}
+
+type unit12 struct {
+	ID      string    `xml:"id,attr"`
+	Resname string    `xml:"resname,attr,omitempty"`
+	Source  string    `xml:"source"`
+	Target  *target12 `xml:"target"`
+	Notes   []string  `xml:"note"`
+}
+
+type target12 struct {
+	State string `xml:"state,attr,omitempty"`
+	Text  string `xml:",chardata"`
+}
+
+func newDocument12(doc *Document) *document12 {
+	file := file12{
+		Original:       doc.Original,
+		SourceLanguage: doc.SourceLanguage,
+		TargetLanguage: doc.TargetLanguage,
+		Datatype:       "plaintext",
+	}
+	for _, unit := range doc.Units {
+		file.Units = append(file.Units, unit12{
+			ID:      unit.Key,
+			Resname: unit.Key,
+			Source:  unit.Source,
+			Target:  &target12{State: state12(unit), Text: unit.Target},
+			Notes:   unit.Notes,
+		})
+	}
+
+	return &document12{
+		Version: Version12,
+		Xmlns:   "urn:oasis:names:tc:xliff:document:1.2",
+		Files:   []file12{file},
+	}
+}
+
+func (root *document12) document() (*Document, error) {
+	if len(root.Files) != 1 {
+		return nil, fmt.Errorf("XLIFF document holds %d files instead of 1", len(root.Files))
+	}
+
+	file := root.Files[0]
+	doc := &Document{
+		Version:        Version12,
+		SourceLanguage: file.SourceLanguage,
+		TargetLanguage: file.TargetLanguage,
+		Original:       file.Original,
+	}
+	for _, u := range file.Units {
+		unit := Unit{Key: u.Resname, Source: u.Source, Notes: u.Notes, State: StateInitial}
+		if unit.Key == "" {
+			unit.Key = u.ID
+		}
+		if u.Target != nil {
+			unit.Target = u.Target.Text
+			unit.State = fromState12(u.Target.State, unit.Target)
+		}
+		doc.Units = append(doc.Units, unit)
+	}
+	return doc, nil
+}
+
+// state12 returns the XLIFF 1.2 target state of a unit
+func state12(unit Unit) string {
+	switch unit.State {
+	case StateTranslated:
+		return "translated"
+	case StateReviewed:
+		return "signed-off"
+	case StateFinal:
+		return "final"
+	default:
+		if unit.Target == "" {
+			return "new"
+		}
+		return "needs-translation"
+	}
+}
+
+// fromState12 converts an XLIFF 1.2 target state. Targets without a state
+// are considered translated.
+func fromState12(state, target string) string {
+	switch state {
+	case "new", "needs-translation", "needs-l10n", "needs-adaptation":
+		return StateInitial
+	case "signed-off":
+		return StateReviewed
+	case "final":
+		return StateFinal
+	case "":
+		if target == "" {
+			return StateInitial
+		}
+		return StateTranslated
+	default:
+		return StateTranslated
+	}
+}
+
+// document20 is the XML structure of an XLIFF 2.0 document
+type document20 struct {
+	XMLName        xml.Name `xml:"xliff"`
+	Version        string   `xml:"version,attr"`
+	Xmlns          string   `xml:"xmlns,attr,omitempty"`
+	SourceLanguage string   `xml:"srcLang,attr"`
+	TargetLanguage string   `xml:"trgLang,attr,omitempty"`
+	Files          []file20 `xml:"file"`
+}
+
+type file20 struct {
+	ID       string   `xml:"id,attr"`
+	Original string   `xml:"original,attr,omitempty"`
+	Units    []unit20 `xml:"unit"`
+}
+
+type unit20 struct {
+	ID       string      `xml:"id,attr"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Notes    []string    `xml:"notes>note,omitempty"`
+	Segments []segment20 `xml:"segment"`
+}
+
+type segment20 struct {
+	State  string  `xml:"state,attr,omitempty"`
+	Source string  `xml:"source"`
+	Target *string `xml:"target"`
+}
+
+// nmtokenRegex matches the values allowed for XLIFF 2.0 identifiers
+var nmtokenRegex = regexp.MustCompile(`^[\w.\-:]+$`)
+
+func newDocument20(doc *Document) *document20 {
+	file := file20{ID: "f1", Original: doc.Original}
+	for i, unit := range doc.Units {
+		// Keys that are not valid identifiers are only kept in the name
+		id := unit.Key
+		if !nmtokenRegex.MatchString(id) {
+			id = fmt.Sprintf("u%d", i+1)
+		}
+
+		target := unit.Target
+		file.Units = append(file.Units, unit20{
+			ID:    id,
+			Name:  unit.Key,
+			Notes: unit.Notes,
+			Segments: []segment20{{
+				State:  state20(unit.State),
+				Source: unit.Source,
+				Target: &target,
+			}},
+		})
+	}
+
+	return &document20{
+		Version:        Version20,
+		Xmlns:          "urn:oasis:names:tc:xliff:document:2.0",
+		SourceLanguage: doc.SourceLanguage,
+		TargetLanguage: doc.TargetLanguage,
+		Files:          []file20{file},
+	}
+}
+
+func (root *document20) document() (*Document, error) {
+	if len(root.Files) != 1 {
+		return nil, fmt.Errorf("XLIFF document holds %d files instead of 1", len(root.Files))
+	}
+
+	file := root.Files[0]
+	doc := &Document{
+		Version:        Version20,
+		SourceLanguage: root.SourceLanguage,
+		TargetLanguage: root.TargetLanguage,
+		Original:       file.Original,
+	}
+	for _, u := range file.Units {
+		unit := Unit{Key: u.Name, Notes: u.Notes, State: StateInitial}
+		if unit.Key == "" {
+			unit.Key = u.ID
+		}
+
+		// Segments of a unit are joined back into a single string, in the
+		// least advanced state of its segments
+		var source, target strings.Builder
+		for i, segment := range u.Segments {
+			source.WriteString(segment.Source)
+			segmentTarget := ""
+			if segment.Target != nil {
+				segmentTarget = *segment.Target
+			}
+			target.WriteString(segmentTarget)
+
+			state := fromState20(segment.State, segmentTarget)
+			if i == 0 || stateRank(state) < stateRank(unit.State) {
+				unit.State = state
+			}
+		}
+		unit.Source = source.String()
+		unit.Target = target.String()
+		doc.Units = append(doc.Units, unit)
+	}
+	return doc, nil
+}
+
+// state20 returns the XLIFF 2.0 segment state of a unit
+func state20(state string) string {
+	if stateRank(state) < 0 {
+		return StateInitial
+	}
+	return state
+}
+
+// fromState20 converts an XLIFF 2.0 segment state. Targets without a
+// state are considered translated.
+func fromState20(state, target string) string {
+	if stateRank(state) >= 0 {
+		return state
+	}
+	if target == "" {
+		return StateInitial
+	}
+	return StateTranslated
+}
+
+// stateRank orders the states from initial to final, unknown states rank -1
+func stateRank(state string) int {
+	switch state {
+	case StateInitial:
+		return 0
+	case StateTranslated:
+		return 1
+	case StateReviewed:
+		return 2
+	case StateFinal:
+		return 3
+	default:
+		return -1
+	}
+}